@@ -0,0 +1,360 @@
+package gogame
+
+import (
+	"encoding/json"
+	"fmt"
+	"io/ioutil"
+	"math"
+	"sort"
+)
+
+// NewActionMap creates an empty action map reading from the provided input.
+func NewActionMap(input Input) *ActionMap {
+	return &ActionMap{
+		Input:    input,
+		bindings: make(map[string][]Binding),
+	}
+}
+
+// ActionMap maps named actions (such as "jump" or "move_x") to keys, mouse buttons and axes.
+// Game code queries actions instead of concrete keys, so controls can be remapped in one place.
+// Input needs to be set for an action map to work properly.
+type ActionMap struct {
+	// Input is used to check the state of the bound keys, buttons and axes.
+	Input
+
+	bindings map[string][]Binding
+}
+
+// Bind adds bindings to an action. An action is triggered by any of its bindings.
+func (m *ActionMap) Bind(action string, bindings ...Binding) {
+	m.bindings[action] = append(m.bindings[action], bindings...)
+}
+
+// Rebind replaces all bindings of an action with the provided ones.
+func (m *ActionMap) Rebind(action string, bindings ...Binding) {
+	m.bindings[action] = append([]Binding(nil), bindings...)
+}
+
+// Unbind removes all bindings of an action.
+func (m *ActionMap) Unbind(action string) {
+	delete(m.bindings, action)
+}
+
+// Bindings returns a copy of the bindings of an action.
+func (m *ActionMap) Bindings(action string) []Binding {
+	return append([]Binding(nil), m.bindings[action]...)
+}
+
+// Actions returns the names of all bound actions in alphabetical order.
+func (m *ActionMap) Actions() []string {
+	var actions []string
+	for action := range m.bindings {
+		actions = append(actions, action)
+	}
+	sort.Strings(actions)
+	return actions
+}
+
+// Pressed checks if an action is currently pressed, that is, if any of its bindings is active.
+func (m *ActionMap) Pressed(action string) bool {
+	for _, b := range m.bindings[action] {
+		if b.down(m.Input) {
+			return true
+		}
+	}
+	return false
+}
+
+// JustPressed checks if an action has just been pressed. Pressing a second binding of an
+// action that is already pressed does not count. A press released within the same frame
+// counts. Mouse axes have no presses, they only count for Pressed and Value.
+func (m *ActionMap) JustPressed(action string) bool {
+	was, now, pressed, _ := m.transition(action)
+	return !was && (now || pressed)
+}
+
+// JustReleased checks if an action has just been released, that is, none of its bindings
+// is active anymore. A press released within the same frame counts too.
+func (m *ActionMap) JustReleased(action string) bool {
+	was, now, _, released := m.transition(action)
+	return !now && (was || released)
+}
+
+// Value returns the analog value of an action. Keys and mouse buttons give 0 or 1, key axes
+// give -1, 0 or 1 and mouse axes give the scaled mouse movement. If an action has more
+// bindings, the value with the largest magnitude wins.
+func (m *ActionMap) Value(action string) float64 {
+	value := 0.0
+	for _, b := range m.bindings[action] {
+		if v := b.value(m.Input); math.Abs(v) > math.Abs(value) {
+			value = v
+		}
+	}
+	return value
+}
+
+// transition reports if an action was active at the start of the frame and is active now, and
+// if any of its bindings got pressed or released since the previous frame. Mouse axes are
+// left out.
+func (m *ActionMap) transition(action string) (was, now, pressed, released bool) {
+	for _, b := range m.bindings[action] {
+		if b.Kind == BindMouseAxis {
+			continue
+		}
+		presses, releases := b.presses(m.Input)
+		was = was || b.wasDown(m.Input)
+		now = now || b.down(m.Input)
+		pressed = pressed || presses > 0
+		released = released || releases > 0
+	}
+	return
+}
+
+// Save writes the bindings of all actions to a file stored at the specified path (in JSON).
+// If the saving fails, it returns an error.
+func (m *ActionMap) Save(path string) error {
+	data, err := json.MarshalIndent(m.bindings, "", "\t")
+	if err != nil {
+		return fmt.Errorf("failed to save bindings: %s", path)
+	}
+	err = ioutil.WriteFile(path, data, 0644)
+	if err != nil {
+		return fmt.Errorf("failed to save bindings: %s", path)
+	}
+	return nil
+}
+
+// Load reads bindings from a file stored at the specified path, previously written by Save.
+// Actions found in the file are rebound, other actions keep their bindings, so defaults can be
+// bound first and then overridden by the user's settings.
+// If the loading fails, it returns an error and the action map stays unchanged.
+func (m *ActionMap) Load(path string) error {
+	data, err := ioutil.ReadFile(path)
+	if err != nil {
+		return fmt.Errorf("failed to load bindings: %s", path)
+	}
+	var bindings map[string][]Binding
+	err = json.Unmarshal(data, &bindings)
+	if err != nil {
+		return fmt.Errorf("failed to load bindings: %s", path)
+	}
+	for action := range bindings {
+		m.Rebind(action, bindings[action]...)
+	}
+	return nil
+}
+
+// BindingKind specifies what a binding is bound to.
+type BindingKind int
+
+// Enumeration of all binding kinds.
+const (
+	BindKey BindingKind = iota
+	BindMouseButton
+	BindKeyAxis
+	BindMouseAxis
+	BindScancode
+	BindScancodeAxis
+)
+
+var bindingKindNames = []string{
+	BindKey:          "key",
+	BindMouseButton:  "mouse_button",
+	BindKeyAxis:      "key_axis",
+	BindMouseAxis:    "mouse_axis",
+	BindScancode:     "scancode",
+	BindScancodeAxis: "scancode_axis",
+}
+
+// String returns the name of a binding kind, such as "key" or "mouse_axis".
+func (k BindingKind) String() string {
+	if k < 0 || int(k) >= len(bindingKindNames) {
+		return fmt.Sprintf("BindingKind(%d)", int(k))
+	}
+	return bindingKindNames[k]
+}
+
+// MarshalText makes binding kinds human-readable in saved bindings.
+func (k BindingKind) MarshalText() ([]byte, error) {
+	if k < 0 || int(k) >= len(bindingKindNames) {
+		return nil, fmt.Errorf("invalid binding kind: %d", int(k))
+	}
+	return []byte(bindingKindNames[k]), nil
+}
+
+// UnmarshalText parses a binding kind from its name.
+func (k *BindingKind) UnmarshalText(text []byte) error {
+	for i, name := range bindingKindNames {
+		if name == string(text) {
+			*k = BindingKind(i)
+			return nil
+		}
+	}
+	return fmt.Errorf("invalid binding kind: %s", text)
+}
+
+// Enumeration of mouse axes usable in mouse axis bindings.
+const (
+	MouseAxisX = iota
+	MouseAxisY
+)
+
+// Binding binds an action to a single key, mouse button or axis.
+// Use KeyBinding, ScancodeBinding, MouseButtonBinding, KeyAxisBinding, ScancodeAxisBinding and
+// MouseAxisBinding to create bindings.
+type Binding struct {
+	Kind BindingKind `json:"kind"`
+
+	// Code is a key, a scancode, a mouse button or a mouse axis, depending on Kind.
+	// For key and scancode axes, Code is the key in the negative direction.
+	Code int `json:"code"`
+
+	// Positive is the key in the positive direction of a key or scancode axis.
+	Positive int `json:"positive,omitempty"`
+
+	// Scale multiplies the value of a mouse axis.
+	Scale float64 `json:"scale,omitempty"`
+}
+
+// KeyBinding creates a binding to a keyboard key.
+func KeyBinding(key int) Binding {
+	return Binding{Kind: BindKey, Code: key}
+}
+
+// ScancodeBinding creates a binding to a physical key, regardless of the keyboard layout.
+func ScancodeBinding(scancode int) Binding {
+	return Binding{Kind: BindScancode, Code: scancode}
+}
+
+// MouseButtonBinding creates a binding to a mouse button.
+func MouseButtonBinding(button int) Binding {
+	return Binding{Kind: BindMouseButton, Code: button}
+}
+
+// KeyAxisBinding creates a composite axis from two keys. The value of the axis is -1 when
+// only the negative key is pressed, 1 when only the positive key is pressed and 0 otherwise.
+func KeyAxisBinding(negative, positive int) Binding {
+	return Binding{Kind: BindKeyAxis, Code: negative, Positive: positive}
+}
+
+// ScancodeAxisBinding creates a composite axis from two physical keys, such as ScancodeA and
+// ScancodeD, regardless of the keyboard layout. It works just like KeyAxisBinding.
+func ScancodeAxisBinding(negative, positive int) Binding {
+	return Binding{Kind: BindScancodeAxis, Code: negative, Positive: positive}
+}
+
+// MouseAxisBinding creates a binding to the movement of the mouse along an axis (MouseAxisX
+// or MouseAxisY). The value of the axis is the mouse delta on that axis multiplied by scale.
+func MouseAxisBinding(axis int, scale float64) Binding {
+	return Binding{Kind: BindMouseAxis, Code: axis, Scale: scale}
+}
+
+func (b Binding) down(in Input) bool {
+	switch b.Kind {
+	case BindKey:
+		return in.KeyDown(b.Code)
+	case BindMouseButton:
+		return in.MouseDown(b.Code)
+	case BindKeyAxis:
+		return in.KeyDown(b.Code) != in.KeyDown(b.Positive)
+	case BindScancode:
+		return in.ScancodeDown(b.Code)
+	case BindScancodeAxis:
+		return in.ScancodeDown(b.Code) != in.ScancodeDown(b.Positive)
+	case BindMouseAxis:
+		return b.value(in) != 0
+	}
+	return false
+}
+
+// presses returns how many times the keys or the mouse button of a binding have been pressed
+// and released since the previous frame. Scancodes are counted by the keys at their positions.
+func (b Binding) presses(in Input) (presses, releases int) {
+	switch b.Kind {
+	case BindKey:
+		return in.KeyPresses(b.Code), in.KeyReleases(b.Code)
+	case BindMouseButton:
+		return in.MousePresses(b.Code), in.MouseReleases(b.Code)
+	case BindKeyAxis:
+		return in.KeyPresses(b.Code) + in.KeyPresses(b.Positive),
+			in.KeyReleases(b.Code) + in.KeyReleases(b.Positive)
+	case BindScancode:
+		key := in.KeyFromScancode(b.Code)
+		return in.KeyPresses(key), in.KeyReleases(key)
+	case BindScancodeAxis:
+		negative, positive := in.KeyFromScancode(b.Code), in.KeyFromScancode(b.Positive)
+		return in.KeyPresses(negative) + in.KeyPresses(positive),
+			in.KeyReleases(negative) + in.KeyReleases(positive)
+	}
+	return 0, 0
+}
+
+// wasDown reconstructs whether a binding was active at the start of the frame from its current
+// state and the presses and releases since the previous frame.
+func (b Binding) wasDown(in Input) bool {
+	// every press and release flips the state, so the difference tells the previous state
+	was := func(down bool, presses, releases int) bool {
+		switch {
+		case presses > releases:
+			return false
+		case releases > presses:
+			return true
+		}
+		return down
+	}
+	keyWasDown := func(key int) bool {
+		return was(in.KeyDown(key), in.KeyPresses(key), in.KeyReleases(key))
+	}
+	scancodeWasDown := func(scancode int) bool {
+		key := in.KeyFromScancode(scancode)
+		return was(in.ScancodeDown(scancode), in.KeyPresses(key), in.KeyReleases(key))
+	}
+	switch b.Kind {
+	case BindKey:
+		return keyWasDown(b.Code)
+	case BindMouseButton:
+		return was(in.MouseDown(b.Code), in.MousePresses(b.Code), in.MouseReleases(b.Code))
+	case BindKeyAxis:
+		return keyWasDown(b.Code) != keyWasDown(b.Positive)
+	case BindScancode:
+		return scancodeWasDown(b.Code)
+	case BindScancodeAxis:
+		return scancodeWasDown(b.Code) != scancodeWasDown(b.Positive)
+	}
+	return false
+}
+
+func (b Binding) value(in Input) float64 {
+	switch b.Kind {
+	case BindKey, BindScancode, BindMouseButton:
+		if b.down(in) {
+			return 1
+		}
+	case BindKeyAxis:
+		value := 0.0
+		if in.KeyDown(b.Code) {
+			value--
+		}
+		if in.KeyDown(b.Positive) {
+			value++
+		}
+		return value
+	case BindScancodeAxis:
+		value := 0.0
+		if in.ScancodeDown(b.Code) {
+			value--
+		}
+		if in.ScancodeDown(b.Positive) {
+			value++
+		}
+		return value
+	case BindMouseAxis:
+		delta := in.MouseDelta()
+		if b.Code == MouseAxisY {
+			return delta.Y * b.Scale
+		}
+		return delta.X * b.Scale
+	}
+	return 0
+}
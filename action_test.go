@@ -0,0 +1,228 @@
+package gogame
+
+import (
+	"path/filepath"
+	"reflect"
+	"testing"
+)
+
+// fakeInput implements the parts of Input used by action maps and input buffers.
+type fakeInput struct {
+	Input
+
+	keys              map[int]bool
+	presses, releases map[int]int
+	mouse             map[int]bool
+	mousePresses      map[int]int
+	mouseReleases     map[int]int
+	mouseDelta        Vec
+	mousePos          Vec
+	touches           []Touch
+}
+
+func newFakeInput() *fakeInput {
+	return &fakeInput{
+		keys:          make(map[int]bool),
+		presses:       make(map[int]int),
+		releases:      make(map[int]int),
+		mouse:         make(map[int]bool),
+		mousePresses:  make(map[int]int),
+		mouseReleases: make(map[int]int),
+	}
+}
+
+// frame starts a new frame, forgetting the presses and releases of the previous one.
+func (in *fakeInput) frame() {
+	in.presses = make(map[int]int)
+	in.releases = make(map[int]int)
+	in.mousePresses = make(map[int]int)
+	in.mouseReleases = make(map[int]int)
+	in.mouseDelta = Vec{}
+	in.touches = nil
+}
+
+func (in *fakeInput) press(key int) {
+	in.keys[key] = true
+	in.presses[key]++
+}
+
+func (in *fakeInput) release(key int) {
+	in.keys[key] = false
+	in.releases[key]++
+}
+
+func (in *fakeInput) KeyDown(key int) bool      { return in.keys[key] }
+func (in *fakeInput) KeyPresses(key int) int    { return in.presses[key] }
+func (in *fakeInput) KeyReleases(key int) int   { return in.releases[key] }
+func (in *fakeInput) MouseDown(button int) bool { return in.mouse[button] }
+func (in *fakeInput) MousePresses(button int) int {
+	return in.mousePresses[button]
+}
+func (in *fakeInput) MouseReleases(button int) int {
+	return in.mouseReleases[button]
+}
+func (in *fakeInput) MouseDelta() Vec { return in.mouseDelta }
+func (in *fakeInput) ScancodeDown(scancode int) bool {
+	return in.keys[in.KeyFromScancode(scancode)]
+}
+func (in *fakeInput) KeyFromScancode(scancode int) int { return scancode }
+
+func TestActionMapTransitions(t *testing.T) {
+	type frame struct {
+		do                                 func(in *fakeInput)
+		pressed, justPressed, justReleased bool
+	}
+	tests := []struct {
+		name     string
+		bindings []Binding
+		frames   []frame
+	}{
+		{
+			name:     "hold and release",
+			bindings: []Binding{KeyBinding(KeySpace)},
+			frames: []frame{
+				{func(in *fakeInput) { in.press(KeySpace) }, true, true, false},
+				{func(in *fakeInput) {}, true, false, false},
+				{func(in *fakeInput) { in.release(KeySpace) }, false, false, true},
+				{func(in *fakeInput) {}, false, false, false},
+			},
+		},
+		{
+			name:     "tap within a frame",
+			bindings: []Binding{KeyBinding(KeySpace)},
+			frames: []frame{
+				{func(in *fakeInput) { in.press(KeySpace); in.release(KeySpace) },
+					false, true, true},
+				{func(in *fakeInput) {}, false, false, false},
+			},
+		},
+		{
+			name:     "release and press again within a frame",
+			bindings: []Binding{KeyBinding(KeySpace)},
+			frames: []frame{
+				{func(in *fakeInput) { in.press(KeySpace) }, true, true, false},
+				{func(in *fakeInput) { in.release(KeySpace); in.press(KeySpace) },
+					true, false, false},
+			},
+		},
+		{
+			name:     "second binding of a pressed action",
+			bindings: []Binding{KeyBinding(KeySpace), KeyBinding(KeyReturn)},
+			frames: []frame{
+				{func(in *fakeInput) { in.press(KeySpace) }, true, true, false},
+				{func(in *fakeInput) { in.press(KeyReturn) }, true, false, false},
+				{func(in *fakeInput) { in.release(KeySpace) }, true, false, false},
+				{func(in *fakeInput) { in.release(KeyReturn) }, false, false, true},
+			},
+		},
+		{
+			name:     "key axis",
+			bindings: []Binding{KeyAxisBinding(KeyLeft, KeyRight)},
+			frames: []frame{
+				{func(in *fakeInput) { in.press(KeyLeft) }, true, true, false},
+				{func(in *fakeInput) { in.press(KeyRight) }, false, false, true},
+				{func(in *fakeInput) { in.release(KeyLeft) }, true, true, false},
+			},
+		},
+		{
+			name:     "scancode",
+			bindings: []Binding{ScancodeBinding(ScancodeA)},
+			frames: []frame{
+				{func(in *fakeInput) { in.press(ScancodeA) }, true, true, false},
+				{func(in *fakeInput) {}, true, false, false},
+			},
+		},
+		{
+			name:     "mouse axis is never just pressed",
+			bindings: []Binding{MouseAxisBinding(MouseAxisX, 1)},
+			frames: []frame{
+				{func(in *fakeInput) { in.mouseDelta = Vec{X: 3} }, true, false, false},
+				{func(in *fakeInput) { in.mouseDelta = Vec{X: 2} }, true, false, false},
+				{func(in *fakeInput) {}, false, false, false},
+			},
+		},
+	}
+
+	for _, test := range tests {
+		in := newFakeInput()
+		m := NewActionMap(in)
+		m.Bind("action", test.bindings...)
+		for i, f := range test.frames {
+			in.frame()
+			f.do(in)
+			got := frame{
+				pressed:      m.Pressed("action"),
+				justPressed:  m.JustPressed("action"),
+				justReleased: m.JustReleased("action"),
+			}
+			if got.pressed != f.pressed || got.justPressed != f.justPressed ||
+				got.justReleased != f.justReleased {
+				t.Errorf("%s, frame %d: got pressed %v, just pressed %v, just released %v, "+
+					"want %v, %v, %v", test.name, i, got.pressed, got.justPressed,
+					got.justReleased, f.pressed, f.justPressed, f.justReleased)
+			}
+		}
+	}
+}
+
+func TestActionMapValue(t *testing.T) {
+	in := newFakeInput()
+	m := NewActionMap(in)
+	m.Bind("move", KeyAxisBinding(KeyLeft, KeyRight), MouseAxisBinding(MouseAxisX, 0.5))
+
+	tests := []struct {
+		keys  []int
+		delta Vec
+		want  float64
+	}{
+		{nil, Vec{}, 0},
+		{[]int{KeyLeft}, Vec{}, -1},
+		{[]int{KeyRight}, Vec{}, 1},
+		{[]int{KeyLeft, KeyRight}, Vec{}, 0},
+		{[]int{KeyLeft}, Vec{X: 6}, 3},
+		{[]int{KeyRight}, Vec{X: -1}, 1},
+	}
+	for _, test := range tests {
+		in.keys = make(map[int]bool)
+		for _, key := range test.keys {
+			in.keys[key] = true
+		}
+		in.mouseDelta = test.delta
+		if got := m.Value("move"); got != test.want {
+			t.Errorf("keys %v, delta %v: got %v, want %v", test.keys, test.delta, got, test.want)
+		}
+	}
+}
+
+func TestActionMapSaveLoad(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "bindings.json")
+
+	saved := NewActionMap(nil)
+	saved.Bind("jump", KeyBinding(KeySpace), MouseButtonBinding(MouseButtonLeft))
+	saved.Bind("move_x", ScancodeAxisBinding(ScancodeA, ScancodeD))
+	saved.Bind("look_x", MouseAxisBinding(MouseAxisX, 0.25))
+	if err := saved.Save(path); err != nil {
+		t.Fatal(err)
+	}
+
+	loaded := NewActionMap(nil)
+	loaded.Bind("jump", KeyBinding(KeyReturn))
+	loaded.Bind("pause", KeyBinding(KeyEscape))
+	if err := loaded.Load(path); err != nil {
+		t.Fatal(err)
+	}
+
+	for _, action := range saved.Actions() {
+		got, want := loaded.Bindings(action), saved.Bindings(action)
+		if !reflect.DeepEqual(got, want) {
+			t.Errorf("%s: got %v, want %v", action, got, want)
+		}
+	}
+	if got := loaded.Bindings("pause"); len(got) != 1 || got[0] != KeyBinding(KeyEscape) {
+		t.Errorf("pause: got %v, want it kept", got)
+	}
+
+	if err := loaded.Load(filepath.Join(t.TempDir(), "missing.json")); err == nil {
+		t.Error("loading a missing file: got no error")
+	}
+}
@@ -0,0 +1,35 @@
+package gogame
+
+// Batch collects many pictures to be drawn at once, which is much faster than drawing them one
+// by one. Consecutive pictures sharing the same underlying picture (e.g. frames of one
+// spritesheet) are submitted to the GPU together. Add pictures to a batch and draw it with
+// DrawBatch.
+//
+// A batch can be drawn many times and reused for the next frame after calling Clear.
+type Batch struct {
+	sprites []batchSprite
+}
+
+type batchSprite struct {
+	rect         Rect
+	pic          *Picture
+	color        Color
+	angle        float64 // around the center of the rect, starts as the rotation of the picture
+	flipX, flipY bool    // set by mirroring cameras
+}
+
+// Add adds a picture to the batch, to be drawn onto a rect (just like with DrawPicture) and
+// masked with a color. The rotation of the picture is kept.
+func (b *Batch) Add(rect Rect, pic *Picture, color Color) {
+	b.sprites = append(b.sprites, batchSprite{rect: rect, pic: pic, color: color, angle: pic.angle})
+}
+
+// Clear removes all pictures from the batch.
+func (b *Batch) Clear() {
+	b.sprites = b.sprites[:0]
+}
+
+// Len returns the number of pictures in the batch.
+func (b *Batch) Len() int {
+	return len(b.sprites)
+}
@@ -0,0 +1,261 @@
+package gogame
+
+import (
+	"bytes"
+	"encoding/xml"
+	"fmt"
+	"io/ioutil"
+	"path/filepath"
+	"strconv"
+	"strings"
+)
+
+// BitmapFont is a font made of pictures, such as a pixel-art font. Create one with LoadBMFont
+// or NewGridFont.
+type BitmapFont struct {
+	glyphs     map[rune]bitmapGlyph
+	kernings   map[[2]rune]float64
+	lineHeight float64
+}
+
+type bitmapGlyph struct {
+	pic     *Picture
+	rect    Rect
+	advance float64
+}
+
+// NewGridFont creates a monospace bitmap font from a picture divided into a grid of cells of
+// the same size, one rune per cell. Chars lists the runes of the cells line-by-line (top to
+// bottom), cell-by-cell (left to right), the way text is written in the picture.
+func NewGridFont(sheet *Picture, cellWidth, cellHeight int, chars string) *BitmapFont {
+	font := &BitmapFont{
+		glyphs:     make(map[rune]bitmapGlyph),
+		kernings:   make(map[[2]rune]float64),
+		lineHeight: float64(cellHeight),
+	}
+
+	// the runes go line-by-line, but Sheet goes column-by-column
+	cells := Sheet(sheet, cellWidth, cellHeight)
+	w, h := sheet.Size()
+	rows, cols := h/cellHeight, w/cellWidth
+
+	i := 0
+	for _, r := range chars {
+		if i >= len(cells) {
+			break
+		}
+		row, col := i/cols, i%cols
+		font.glyphs[r] = bitmapGlyph{
+			pic:     cells[col*rows+row],
+			rect:    Rect{X: 0, Y: 0, W: float64(cellWidth), H: float64(cellHeight)},
+			advance: float64(cellWidth),
+		}
+		i++
+	}
+
+	return font
+}
+
+// LoadBMFont loads an AngelCode BMFont from a .fnt file stored at the specified path. Both the
+// text and the XML format are supported. Page pictures are loaded relative to the .fnt file.
+// If the loading fails, an error is returned.
+func LoadBMFont(path string) (*BitmapFont, error) {
+	content, err := ioutil.ReadFile(path)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load font: %s", path)
+	}
+
+	var desc bmFont
+	if bytes.HasPrefix(bytes.TrimSpace(content), []byte("<")) {
+		err = xml.Unmarshal(content, &desc)
+	} else {
+		err = desc.parseText(string(content))
+	}
+	if err != nil {
+		return nil, fmt.Errorf("failed to load font: %s", path)
+	}
+
+	pages := make(map[int]*Picture)
+	for _, page := range desc.Pages {
+		pages[page.ID], err = LoadPicture(filepath.Join(filepath.Dir(path), page.File))
+		if err != nil {
+			return nil, fmt.Errorf("failed to load font: %s", path)
+		}
+	}
+
+	font := &BitmapFont{
+		glyphs:     make(map[rune]bitmapGlyph),
+		kernings:   make(map[[2]rune]float64),
+		lineHeight: float64(desc.Common.LineHeight),
+	}
+	for _, char := range desc.Chars {
+		glyph := bitmapGlyph{
+			rect: Rect{
+				X: float64(char.XOffset),
+				Y: float64(char.YOffset),
+				W: float64(char.Width),
+				H: float64(char.Height),
+			},
+			advance: float64(char.XAdvance),
+		}
+		if char.Width > 0 && char.Height > 0 {
+			page := pages[char.Page]
+			if page == nil {
+				return nil, fmt.Errorf("failed to load font: %s", path)
+			}
+			glyph.pic = page.Slice(char.X, char.Y, char.Width, char.Height)
+		}
+		font.glyphs[rune(char.ID)] = glyph
+	}
+	for _, kerning := range desc.Kernings {
+		font.kernings[[2]rune{rune(kerning.First), rune(kerning.Second)}] = float64(kerning.Amount)
+	}
+
+	return font, nil
+}
+
+// Glyph returns the picture of a rune. Runes missing in the font are invisible and don't
+// advance the pen.
+func (f *BitmapFont) Glyph(r rune) (pic *Picture, rect Rect, advance float64) {
+	glyph := f.glyphs[r]
+	return glyph.pic, glyph.rect, glyph.advance
+}
+
+// Kerning returns the kerning between two runes as specified by the font.
+func (f *BitmapFont) Kerning(a, b rune) float64 {
+	return f.kernings[[2]rune{a, b}]
+}
+
+// LineHeight returns the distance between two lines of the font.
+func (f *BitmapFont) LineHeight() float64 {
+	return f.lineHeight
+}
+
+// bmFont is the description of a font in a BMFont file. The XML tags match the XML format,
+// the text format is parsed into the same structure.
+type bmFont struct {
+	XMLName xml.Name `xml:"font"`
+
+	Common struct {
+		LineHeight int `xml:"lineHeight,attr"`
+		Base       int `xml:"base,attr"`
+	} `xml:"common"`
+
+	Pages    []bmPage    `xml:"pages>page"`
+	Chars    []bmChar    `xml:"chars>char"`
+	Kernings []bmKerning `xml:"kernings>kerning"`
+}
+
+type bmPage struct {
+	ID   int    `xml:"id,attr"`
+	File string `xml:"file,attr"`
+}
+
+type bmChar struct {
+	ID       int `xml:"id,attr"`
+	X        int `xml:"x,attr"`
+	Y        int `xml:"y,attr"`
+	Width    int `xml:"width,attr"`
+	Height   int `xml:"height,attr"`
+	XOffset  int `xml:"xoffset,attr"`
+	YOffset  int `xml:"yoffset,attr"`
+	XAdvance int `xml:"xadvance,attr"`
+	Page     int `xml:"page,attr"`
+}
+
+type bmKerning struct {
+	First  int `xml:"first,attr"`
+	Second int `xml:"second,attr"`
+	Amount int `xml:"amount,attr"`
+}
+
+// parseText parses the text format of BMFont. Each line is a tag followed by key=value pairs,
+// e.g. `char id=65 x=10 y=0 width=8 height=12 xoffset=0 yoffset=2 xadvance=9 page=0`.
+func (desc *bmFont) parseText(content string) error {
+	for _, line := range strings.Split(content, "\n") {
+		tag, attrs, err := parseBMFontLine(line)
+		if err != nil {
+			return err
+		}
+		atoi := func(key string) int {
+			n, e := strconv.Atoi(attrs[key])
+			if e != nil && attrs[key] != "" && err == nil {
+				err = e
+			}
+			return n
+		}
+
+		switch tag {
+		case "common":
+			desc.Common.LineHeight = atoi("lineHeight")
+			desc.Common.Base = atoi("base")
+		case "page":
+			desc.Pages = append(desc.Pages, bmPage{ID: atoi("id"), File: attrs["file"]})
+		case "char":
+			desc.Chars = append(desc.Chars, bmChar{
+				ID:       atoi("id"),
+				X:        atoi("x"),
+				Y:        atoi("y"),
+				Width:    atoi("width"),
+				Height:   atoi("height"),
+				XOffset:  atoi("xoffset"),
+				YOffset:  atoi("yoffset"),
+				XAdvance: atoi("xadvance"),
+				Page:     atoi("page"),
+			})
+		case "kerning":
+			desc.Kernings = append(desc.Kernings, bmKerning{
+				First:  atoi("first"),
+				Second: atoi("second"),
+				Amount: atoi("amount"),
+			})
+		}
+
+		if err != nil {
+			return err
+		}
+	}
+	return nil
+}
+
+// parseBMFontLine splits a line of the BMFont text format into its tag and attributes.
+// Values may be quoted to contain spaces.
+func parseBMFontLine(line string) (tag string, attrs map[string]string, err error) {
+	line = strings.TrimSpace(line)
+	attrs = make(map[string]string)
+
+	end := strings.IndexAny(line, " \t")
+	if end < 0 {
+		return line, attrs, nil
+	}
+	tag, line = line[:end], strings.TrimSpace(line[end:])
+
+	for line != "" {
+		eq := strings.IndexByte(line, '=')
+		if eq < 0 {
+			return "", nil, fmt.Errorf("missing '=' in BMFont line: %s", line)
+		}
+		key := strings.TrimSpace(line[:eq])
+		line = line[eq+1:]
+
+		var value string
+		if strings.HasPrefix(line, `"`) {
+			closing := strings.IndexByte(line[1:], '"')
+			if closing < 0 {
+				return "", nil, fmt.Errorf("unterminated quote in BMFont line: %s", line)
+			}
+			value, line = line[1:closing+1], line[closing+2:]
+		} else {
+			end := strings.IndexAny(line, " \t")
+			if end < 0 {
+				end = len(line)
+			}
+			value, line = line[:end], line[end:]
+		}
+
+		attrs[key] = value
+		line = strings.TrimSpace(line)
+	}
+
+	return tag, attrs, nil
+}
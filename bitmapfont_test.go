@@ -0,0 +1,130 @@
+package gogame
+
+import (
+	"encoding/xml"
+	"reflect"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+const bmFontText = `info face="Pixel Sans" size=12
+common lineHeight=14 base=11 scaleW=128 scaleH=128 pages=1
+page id=0 file="pixel sans_0.png"
+chars count=2
+char id=65 x=10 y=0 width=8 height=12 xoffset=0 yoffset=2 xadvance=9 page=0
+char id=32 x=0 y=0 width=0 height=0 xoffset=0 yoffset=0 xadvance=4 page=0
+kernings count=1
+kerning first=65 second=86 amount=-1
+`
+
+const bmFontXML = `<?xml version="1.0"?>
+<font>
+  <info face="Pixel Sans" size="12"/>
+  <common lineHeight="14" base="11" scaleW="128" scaleH="128" pages="1"/>
+  <pages>
+    <page id="0" file="pixel sans_0.png"/>
+  </pages>
+  <chars count="2">
+    <char id="65" x="10" y="0" width="8" height="12" xoffset="0" yoffset="2" xadvance="9" page="0"/>
+    <char id="32" x="0" y="0" width="0" height="0" xoffset="0" yoffset="0" xadvance="4" page="0"/>
+  </chars>
+  <kernings count="1">
+    <kerning first="65" second="86" amount="-1"/>
+  </kernings>
+</font>
+`
+
+func TestBMFontParsing(t *testing.T) {
+	var want bmFont
+	want.Common.LineHeight = 14
+	want.Common.Base = 11
+	want.Pages = []bmPage{{ID: 0, File: "pixel sans_0.png"}}
+	want.Chars = []bmChar{
+		{ID: 65, X: 10, Y: 0, Width: 8, Height: 12, XOffset: 0, YOffset: 2, XAdvance: 9},
+		{ID: 32, XAdvance: 4},
+	}
+	want.Kernings = []bmKerning{{First: 65, Second: 86, Amount: -1}}
+
+	var text bmFont
+	if err := text.parseText(bmFontText); err != nil {
+		t.Fatal(err)
+	}
+	if !reflect.DeepEqual(text, want) {
+		t.Errorf("text format: got %+v, want %+v", text, want)
+	}
+
+	var fromXML bmFont
+	if err := xml.Unmarshal([]byte(bmFontXML), &fromXML); err != nil {
+		t.Fatal(err)
+	}
+	fromXML.XMLName = xml.Name{}
+	if !reflect.DeepEqual(fromXML, want) {
+		t.Errorf("XML format: got %+v, want %+v", fromXML, want)
+	}
+}
+
+func TestBMFontLineErrors(t *testing.T) {
+	tests := []struct {
+		line string
+		ok   bool
+	}{
+		{`char id=65 x=10`, true},
+		{`page id=0 file="with spaces.png"`, true},
+		{`chars`, true},
+		{`char id`, false},
+		{`page id=0 file="unterminated`, false},
+	}
+	for _, test := range tests {
+		var desc bmFont
+		err := desc.parseText(test.line)
+		if (err == nil) != test.ok {
+			t.Errorf("%q: got error %v, want ok %v", test.line, err, test.ok)
+		}
+	}
+
+	var desc bmFont
+	if err := desc.parseText(`char id=A`); err == nil {
+		t.Error("non-numeric id: got no error")
+	}
+}
+
+func TestGridFontCells(t *testing.T) {
+	surface, err := sdl.CreateRGBSurface(0, 32, 16, 32, 0, 0, 0, 0)
+	if err != nil {
+		t.Skip("can't create a surface:", err)
+	}
+	defer surface.Free()
+	sheet := &Picture{surface: surface, rect: sdl.Rect{W: 32, H: 16}}
+
+	// the cells go line-by-line, unlike the frames of Sheet
+	font := NewGridFont(sheet, 8, 8, "abcdefghij")
+	tests := []struct {
+		r    rune
+		cell sdl.Rect
+	}{
+		{'a', sdl.Rect{X: 0, Y: 0, W: 8, H: 8}},
+		{'b', sdl.Rect{X: 8, Y: 0, W: 8, H: 8}},
+		{'d', sdl.Rect{X: 24, Y: 0, W: 8, H: 8}},
+		{'e', sdl.Rect{X: 0, Y: 8, W: 8, H: 8}},
+		{'h', sdl.Rect{X: 24, Y: 8, W: 8, H: 8}},
+	}
+	for _, test := range tests {
+		pic, rect, advance := font.Glyph(test.r)
+		if pic == nil || pic.rect != test.cell {
+			t.Errorf("%q: got cell %v, want %v", test.r, pic, test.cell)
+			continue
+		}
+		if rect != (Rect{W: 8, H: 8}) || advance != 8 {
+			t.Errorf("%q: got rect %v and advance %v, want an 8x8 cell", test.r, rect, advance)
+		}
+	}
+	if pic, _, _ := font.Glyph('i'); pic != nil {
+		t.Errorf("'i' is beyond the last cell: got %v, want nil", pic)
+	}
+
+	frames := Sheet(sheet, 8, 8)
+	if len(frames) != 8 || frames[1].rect != (sdl.Rect{X: 0, Y: 8, W: 8, H: 8}) {
+		t.Errorf("Sheet: got second frame %v, want the one below the first", frames[1].rect)
+	}
+}
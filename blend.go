@@ -0,0 +1,119 @@
+package gogame
+
+import "github.com/veandco/go-sdl2/sdl"
+
+// BlendMode specifies how the colors being drawn (source) are combined with the colors
+// already on a video output (destination).
+type BlendMode sdl.BlendMode
+
+// Enumeration of the common blend modes. These are supported by all video outputs.
+const (
+	// BlendAlpha is the default mode: dst = src * srcA + dst * (1 - srcA).
+	BlendAlpha = BlendMode(sdl.BLENDMODE_BLEND)
+
+	// BlendAdditive brightens, useful for glows and lights: dst = src * srcA + dst.
+	BlendAdditive = BlendMode(sdl.BLENDMODE_ADD)
+
+	// BlendMultiply darkens, useful for shadows: dst = src * dst + dst * (1 - srcA).
+	BlendMultiply = BlendMode(0x8) // SDL_BLENDMODE_MUL, go-sdl2 doesn't have it
+
+	// BlendModulate multiplies the colors, ignoring alpha: dst = src * dst.
+	BlendModulate = BlendMode(sdl.BLENDMODE_MOD)
+
+	// BlendNone replaces the destination, including its alpha: dst = src.
+	BlendNone = BlendMode(sdl.BLENDMODE_NONE)
+)
+
+// BlendScreen brightens like BlendAdditive, but never overexposes:
+// dst = src * (1 - dst) + dst. The destination alpha is kept.
+var BlendScreen = NewBlendMode(
+	FactorOneMinusDstColor, FactorOne, OpAdd,
+	FactorZero, FactorOne, OpAdd,
+)
+
+// premultipliedBlendModes replace blend modes when drawing textures with premultiplied colors
+// (color channels already multiplied by alpha), such as those of target canvases, so that they
+// blend the same as textures with straight colors. The other blend modes use the alpha of the
+// source differently or not at all, so they have no equivalent, and pictures of target
+// canvases are read back and drawn with straight colors for them.
+var premultipliedBlendModes = map[BlendMode]BlendMode{
+	BlendAlpha: NewBlendMode(
+		FactorOne, FactorOneMinusSrcAlpha, OpAdd,
+		FactorOne, FactorOneMinusSrcAlpha, OpAdd,
+	),
+	BlendAdditive: NewBlendMode(
+		FactorOne, FactorOne, OpAdd,
+		FactorZero, FactorOne, OpAdd,
+	),
+}
+
+// BlendFactor is what a source or destination color is multiplied by in a custom blend mode.
+type BlendFactor int
+
+// Enumeration of all blend factors.
+const (
+	FactorZero BlendFactor = iota
+	FactorOne
+	FactorSrcColor
+	FactorOneMinusSrcColor
+	FactorSrcAlpha
+	FactorOneMinusSrcAlpha
+	FactorDstColor
+	FactorOneMinusDstColor
+	FactorDstAlpha
+	FactorOneMinusDstAlpha
+)
+
+var sdlBlendFactors = [...]sdl.BlendFactor{
+	FactorZero:             sdl.BLENDFACTOR_ZERO,
+	FactorOne:              sdl.BLENDFACTOR_ONE,
+	FactorSrcColor:         sdl.BLENDFACTOR_SRC_COLOR,
+	FactorOneMinusSrcColor: sdl.BLENDFACTOR_ONE_MINUS_SRC_COLOR,
+	FactorSrcAlpha:         sdl.BLENDFACTOR_SRC_ALPHA,
+	FactorOneMinusSrcAlpha: sdl.BLENDFACTOR_ONE_MINUS_SRC_ALPHA,
+	FactorDstColor:         sdl.BLENDFACTOR_DST_COLOR,
+	FactorOneMinusDstColor: sdl.BLENDFACTOR_ONE_MINUS_DST_COLOR,
+	FactorDstAlpha:         sdl.BLENDFACTOR_DST_ALPHA,
+	FactorOneMinusDstAlpha: sdl.BLENDFACTOR_ONE_MINUS_DST_ALPHA,
+}
+
+// BlendOperation is how the multiplied source and destination are combined in a custom blend
+// mode.
+type BlendOperation int
+
+// Enumeration of all blend operations.
+const (
+	OpAdd         BlendOperation = iota // src + dst
+	OpSubtract                          // src - dst
+	OpRevSubtract                       // dst - src
+	OpMinimum                           // min(src, dst)
+	OpMaximum                           // max(src, dst)
+)
+
+var sdlBlendOperations = [...]sdl.BlendOperation{
+	OpAdd:         sdl.BLENDOPERATION_ADD,
+	OpSubtract:    sdl.BLENDOPERATION_SUBTRACT,
+	OpRevSubtract: sdl.BLENDOPERATION_REV_SUBTRACT,
+	OpMinimum:     sdl.BLENDOPERATION_MINIMUM,
+	OpMaximum:     sdl.BLENDOPERATION_MAXIMUM,
+}
+
+// NewBlendMode creates a custom blend mode. The color channels are blended as
+// colorOp(src * srcColor, dst * dstColor) and the alpha channel as
+// alphaOp(srcA * srcAlpha, dstA * dstAlpha).
+//
+// Custom blend modes are not supported by every renderer (e.g. not by canvases). Where they
+// are not supported, drawing falls back to BlendAlpha.
+func NewBlendMode(
+	srcColor, dstColor BlendFactor, colorOp BlendOperation,
+	srcAlpha, dstAlpha BlendFactor, alphaOp BlendOperation,
+) BlendMode {
+	return BlendMode(sdl.ComposeCustomBlendMode(
+		sdlBlendFactors[srcColor],
+		sdlBlendFactors[dstColor],
+		sdlBlendOperations[colorOp],
+		sdlBlendFactors[srcAlpha],
+		sdlBlendFactors[dstAlpha],
+		sdlBlendOperations[alphaOp],
+	))
+}
@@ -0,0 +1,114 @@
+package gogame
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// sdlBlendMode is a custom blend mode taken apart into the fields SDL composes it of.
+type sdlBlendMode struct {
+	srcColor, dstColor sdl.BlendFactor
+	colorOp            sdl.BlendOperation
+	srcAlpha, dstAlpha sdl.BlendFactor
+	alphaOp            sdl.BlendOperation
+}
+
+// decompose takes a custom blend mode apart, as laid out by SDL_ComposeCustomBlendMode.
+func decompose(mode BlendMode) sdlBlendMode {
+	return sdlBlendMode{
+		srcColor: sdl.BlendFactor(mode >> 4 & 0xF),
+		dstColor: sdl.BlendFactor(mode >> 8 & 0xF),
+		colorOp:  sdl.BlendOperation(mode & 0xF),
+		srcAlpha: sdl.BlendFactor(mode >> 20 & 0xF),
+		dstAlpha: sdl.BlendFactor(mode >> 24 & 0xF),
+		alphaOp:  sdl.BlendOperation(mode >> 16 & 0xF),
+	}
+}
+
+func TestNewBlendMode(t *testing.T) {
+	tests := []struct {
+		name string
+		mode BlendMode
+		want sdlBlendMode
+	}{
+		{
+			"screen",
+			BlendScreen,
+			sdlBlendMode{
+				sdl.BLENDFACTOR_ONE_MINUS_DST_COLOR, sdl.BLENDFACTOR_ONE, sdl.BLENDOPERATION_ADD,
+				sdl.BLENDFACTOR_ZERO, sdl.BLENDFACTOR_ONE, sdl.BLENDOPERATION_ADD,
+			},
+		},
+		{
+			"alpha factors",
+			NewBlendMode(
+				FactorSrcAlpha, FactorOneMinusSrcAlpha, OpSubtract,
+				FactorDstAlpha, FactorOneMinusDstAlpha, OpMaximum,
+			),
+			sdlBlendMode{
+				sdl.BLENDFACTOR_SRC_ALPHA, sdl.BLENDFACTOR_ONE_MINUS_SRC_ALPHA,
+				sdl.BLENDOPERATION_SUBTRACT,
+				sdl.BLENDFACTOR_DST_ALPHA, sdl.BLENDFACTOR_ONE_MINUS_DST_ALPHA,
+				sdl.BLENDOPERATION_MAXIMUM,
+			},
+		},
+		{
+			"color factors",
+			NewBlendMode(
+				FactorSrcColor, FactorOneMinusSrcColor, OpRevSubtract,
+				FactorDstColor, FactorZero, OpMinimum,
+			),
+			sdlBlendMode{
+				sdl.BLENDFACTOR_SRC_COLOR, sdl.BLENDFACTOR_ONE_MINUS_SRC_COLOR,
+				sdl.BLENDOPERATION_REV_SUBTRACT,
+				sdl.BLENDFACTOR_DST_COLOR, sdl.BLENDFACTOR_ZERO, sdl.BLENDOPERATION_MINIMUM,
+			},
+		},
+		{
+			"premultiplied alpha",
+			premultipliedBlendModes[BlendAlpha],
+			sdlBlendMode{
+				sdl.BLENDFACTOR_ONE, sdl.BLENDFACTOR_ONE_MINUS_SRC_ALPHA, sdl.BLENDOPERATION_ADD,
+				sdl.BLENDFACTOR_ONE, sdl.BLENDFACTOR_ONE_MINUS_SRC_ALPHA, sdl.BLENDOPERATION_ADD,
+			},
+		},
+		{
+			"premultiplied additive",
+			premultipliedBlendModes[BlendAdditive],
+			sdlBlendMode{
+				sdl.BLENDFACTOR_ONE, sdl.BLENDFACTOR_ONE, sdl.BLENDOPERATION_ADD,
+				sdl.BLENDFACTOR_ZERO, sdl.BLENDFACTOR_ONE, sdl.BLENDOPERATION_ADD,
+			},
+		},
+	}
+	for _, test := range tests {
+		if got := decompose(test.mode); got != test.want {
+			t.Errorf("%s: got %+v, want %+v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestPremultipliedBlendModes(t *testing.T) {
+	tests := []struct {
+		name       string
+		mode       BlendMode
+		equivalent bool
+	}{
+		{"alpha", BlendAlpha, true},
+		{"additive", BlendAdditive, true},
+		{"multiply", BlendMultiply, false},
+		{"modulate", BlendModulate, false},
+		{"none", BlendNone, false},
+		{"screen", BlendScreen, false},
+	}
+	for _, test := range tests {
+		premultiplied, ok := premultipliedBlendModes[test.mode]
+		if ok != test.equivalent {
+			t.Errorf("%s: got an equivalent %v, want %v", test.name, ok, test.equivalent)
+		}
+		if ok && premultiplied == test.mode {
+			t.Errorf("%s: got the same mode for premultiplied colors", test.name)
+		}
+	}
+}
@@ -0,0 +1,174 @@
+package gogame
+
+// NewInputBuffer creates an input buffer recording presses of the actions of an action map.
+// Presses are remembered for the specified time (in seconds).
+func NewInputBuffer(actions *ActionMap, memory float64) *InputBuffer {
+	return &InputBuffer{
+		Actions:   actions,
+		Memory:    memory,
+		sequences: make(map[string]Sequence),
+		consumed:  make(map[string]float64),
+	}
+}
+
+// InputBuffer records timestamped presses of actions and matches them against declared
+// sequences, such as fighting-game style motions (down, down-forward, forward + punch) or
+// double-taps. It also remembers recent presses, so that a jump pressed a few frames before
+// landing can still trigger. Call Update once per frame.
+// Actions needs to be set for an input buffer to work properly.
+type InputBuffer struct {
+	// Actions are the actions whose presses are recorded.
+	Actions *ActionMap
+
+	// Memory is how long (in seconds) presses are remembered. It limits the total duration
+	// of a sequence.
+	Memory float64
+
+	time      float64
+	entries   []bufferEntry
+	sequences map[string]Sequence
+	consumed  map[string]float64
+}
+
+// Sequence is a series of steps which need to be pressed one after another.
+type Sequence struct {
+	// Steps are the steps of a sequence. Each step is a set of actions which need to be
+	// pressed together, e.g. {"down", "forward"} for down-forward. A step is entered when the
+	// last of its actions gets pressed while the others are held.
+	Steps [][]string
+
+	// Window is the longest time (in seconds) between two consecutive steps.
+	Window float64
+}
+
+// bufferEntry is a moment when at least one action got pressed.
+type bufferEntry struct {
+	time    float64
+	pressed []string
+	held    []string
+}
+
+// Define declares a named sequence. A sequence with the same name is replaced.
+func (b *InputBuffer) Define(name string, seq Sequence) {
+	b.sequences[name] = seq
+}
+
+// Update records the actions pressed in this frame and forgets old presses. Dt is the time
+// that passed since the previous call to Update.
+func (b *InputBuffer) Update(dt float64) {
+	b.time += dt
+
+	var entry bufferEntry
+	for _, action := range b.Actions.Actions() {
+		if b.Actions.JustPressed(action) {
+			entry.pressed = append(entry.pressed, action)
+		}
+		if b.Actions.Pressed(action) {
+			entry.held = append(entry.held, action)
+		}
+	}
+	if len(entry.pressed) > 0 {
+		entry.time = b.time
+		b.entries = append(b.entries, entry)
+	}
+
+	forget := 0
+	for forget < len(b.entries) && b.entries[forget].time < b.time-b.Memory {
+		forget++
+	}
+	b.entries = b.entries[forget:]
+}
+
+// Buffered checks if an action has been pressed within the last window seconds and the press
+// has not been consumed yet. Buffered(action, 0) is the same as JustPressed on the action map.
+func (b *InputBuffer) Buffered(action string, window float64) bool {
+	for i := len(b.entries) - 1; i >= 0; i-- {
+		e := b.entries[i]
+		if e.time < b.time-window || !b.fresh("action:"+action, e) {
+			break
+		}
+		if contains(e.pressed, action) {
+			return true
+		}
+	}
+	return false
+}
+
+// Consume marks all buffered presses of an action as used, so that they don't trigger again.
+func (b *InputBuffer) Consume(action string) {
+	b.consumed["action:"+action] = b.time
+}
+
+// Matched checks if a sequence has been completed within the last window seconds and has not
+// been consumed yet. Matched(name, 0) only checks if the sequence has been completed in this
+// frame. Presses of other actions between the steps of a sequence are ignored.
+func (b *InputBuffer) Matched(name string, window float64) bool {
+	seq, ok := b.sequences[name]
+	if !ok || len(seq.Steps) == 0 {
+		return false
+	}
+	for i := len(b.entries) - 1; i >= 0; i-- {
+		e := b.entries[i]
+		if e.time < b.time-window || !b.fresh("sequence:"+name, e) {
+			break
+		}
+		if b.matchFrom(name, seq, len(seq.Steps)-1, i) {
+			return true
+		}
+	}
+	return false
+}
+
+// ConsumeSequence marks all completions of a sequence as used, so that they don't trigger
+// again. The presses still count for other sequences.
+func (b *InputBuffer) ConsumeSequence(name string) {
+	b.consumed["sequence:"+name] = b.time
+}
+
+// matchFrom checks if the steps up to step can be matched with entry i matching step.
+func (b *InputBuffer) matchFrom(name string, seq Sequence, step, i int) bool {
+	if !stepEntered(seq.Steps[step], b.entries[i]) {
+		return false
+	}
+	if step == 0 {
+		return true
+	}
+	for j := i - 1; j >= 0; j-- {
+		e := b.entries[j]
+		if e.time < b.entries[i].time-seq.Window || !b.fresh("sequence:"+name, e) {
+			break
+		}
+		if b.matchFrom(name, seq, step-1, j) {
+			return true
+		}
+	}
+	return false
+}
+
+// fresh checks if an entry happened after the last consumption of key.
+func (b *InputBuffer) fresh(key string, e bufferEntry) bool {
+	consumed, ok := b.consumed[key]
+	return !ok || e.time > consumed
+}
+
+func stepEntered(step []string, e bufferEntry) bool {
+	entered := false
+	for _, action := range step {
+		if !contains(e.held, action) && !contains(e.pressed, action) {
+			return false
+		}
+		if contains(e.pressed, action) {
+			entered = true
+		}
+	}
+	return entered
+}
+
+func contains(list []string, s string) bool {
+	for _, t := range list {
+		if t == s {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,163 @@
+package gogame
+
+import "testing"
+
+// bufferFrame is one frame of an input buffer test: keys pressed and released in it, in order.
+type bufferFrame struct {
+	press, release []int
+	tap            []int // pressed and released within the frame
+}
+
+func runBuffer(b *InputBuffer, in *fakeInput, dt float64, f bufferFrame) {
+	in.frame()
+	for _, key := range f.release {
+		in.release(key)
+	}
+	for _, key := range f.press {
+		in.press(key)
+	}
+	for _, key := range f.tap {
+		in.press(key)
+		in.release(key)
+	}
+	b.Update(dt)
+}
+
+func newTestBuffer() (*InputBuffer, *fakeInput) {
+	in := newFakeInput()
+	actions := NewActionMap(in)
+	actions.Bind("down", KeyBinding(KeyDown))
+	actions.Bind("forward", KeyBinding(KeyRight))
+	actions.Bind("punch", KeyBinding(KeyX))
+	actions.Bind("jump", KeyBinding(KeySpace))
+
+	b := NewInputBuffer(actions, 1)
+	b.Define("fireball", Sequence{
+		Steps:  [][]string{{"down"}, {"down", "forward"}, {"forward"}, {"punch"}},
+		Window: 0.2,
+	})
+	b.Define("dash", Sequence{
+		Steps:  [][]string{{"forward"}, {"forward"}},
+		Window: 0.25,
+	})
+	return b, in
+}
+
+func TestInputBufferSequences(t *testing.T) {
+	tests := []struct {
+		name   string
+		seq    string
+		frames []bufferFrame
+		want   bool
+	}{
+		{"fireball", "fireball", []bufferFrame{
+			{press: []int{KeyDown}},
+			{press: []int{KeyRight}},
+			{release: []int{KeyDown}},
+			{press: []int{KeyX}},
+		}, false}, // releasing down doesn't press forward
+		{"fireball with forward pressed again", "fireball", []bufferFrame{
+			{press: []int{KeyDown}},
+			{press: []int{KeyRight}},
+			{release: []int{KeyDown, KeyRight}},
+			{press: []int{KeyRight}},
+			{press: []int{KeyX}},
+		}, true},
+		{"fireball with other presses between", "fireball", []bufferFrame{
+			{press: []int{KeyDown}},
+			{press: []int{KeyRight}},
+			{release: []int{KeyDown, KeyRight}, tap: []int{KeySpace}},
+			{press: []int{KeyRight}},
+			{press: []int{KeyX}},
+		}, true},
+		{"fireball too slow", "fireball", []bufferFrame{
+			{press: []int{KeyDown}},
+			{press: []int{KeyRight}},
+			{release: []int{KeyDown, KeyRight}},
+			{},
+			{},
+			{},
+			{},
+			{press: []int{KeyRight}},
+			{press: []int{KeyX}},
+		}, false},
+		{"double tap", "dash", []bufferFrame{
+			{press: []int{KeyRight}},
+			{release: []int{KeyRight}},
+			{press: []int{KeyRight}},
+		}, true},
+		{"double tap within single frames", "dash", []bufferFrame{
+			{tap: []int{KeyRight}},
+			{tap: []int{KeyRight}},
+		}, true},
+		{"held is no double tap", "dash", []bufferFrame{
+			{press: []int{KeyRight}},
+			{},
+			{},
+		}, false},
+	}
+
+	for _, test := range tests {
+		b, in := newTestBuffer()
+		for _, f := range test.frames {
+			runBuffer(b, in, 0.05, f)
+		}
+		if got := b.Matched(test.seq, 0); got != test.want {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+	}
+}
+
+func TestInputBufferBuffered(t *testing.T) {
+	b, in := newTestBuffer()
+
+	// a jump tapped within a single frame, a few frames before landing
+	runBuffer(b, in, 0.05, bufferFrame{tap: []int{KeySpace}})
+	runBuffer(b, in, 0.05, bufferFrame{})
+	runBuffer(b, in, 0.05, bufferFrame{})
+
+	if !b.Buffered("jump", 0.15) {
+		t.Error("tapped jump: got not buffered, want buffered")
+	}
+	if b.Buffered("jump", 0.05) {
+		t.Error("tapped jump out of the window: got buffered, want not buffered")
+	}
+
+	b.Consume("jump")
+	if b.Buffered("jump", 1) {
+		t.Error("consumed jump: got buffered, want not buffered")
+	}
+
+	runBuffer(b, in, 0.05, bufferFrame{press: []int{KeySpace}})
+	if !b.Buffered("jump", 0) {
+		t.Error("jump pressed after consuming: got not buffered, want buffered")
+	}
+
+	runBuffer(b, in, 2, bufferFrame{})
+	if b.Buffered("jump", 10) {
+		t.Error("jump older than the memory: got buffered, want forgotten")
+	}
+}
+
+func TestInputBufferConsumeSequence(t *testing.T) {
+	b, in := newTestBuffer()
+	runBuffer(b, in, 0.1, bufferFrame{tap: []int{KeyRight}})
+	runBuffer(b, in, 0.1, bufferFrame{tap: []int{KeyRight}})
+	if !b.Matched("dash", 0) {
+		t.Fatal("dash: got not matched, want matched")
+	}
+	b.ConsumeSequence("dash")
+	if b.Matched("dash", 1) {
+		t.Error("consumed dash: got matched, want not matched")
+	}
+
+	// the third tap makes a new dash with the second one only if they are fresh
+	runBuffer(b, in, 0.1, bufferFrame{tap: []int{KeyRight}})
+	if b.Matched("dash", 0) {
+		t.Error("dash from a consumed tap: got matched, want not matched")
+	}
+	runBuffer(b, in, 0.1, bufferFrame{tap: []int{KeyRight}})
+	if !b.Matched("dash", 0) {
+		t.Error("dash from fresh taps: got not matched, want matched")
+	}
+}
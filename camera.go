@@ -1,6 +1,6 @@
 package gogame
 
-// Camera allows moving and zooming the game view.
+// Camera allows moving, zooming and rotating the game view.
 // It achieves so by transfroming points between so called 'game space' and 'display space'.
 // Game space represents coordinates used internally inside a game.
 // Display space represents coordinates of the VideoOutput.
@@ -14,20 +14,109 @@ type Camera struct {
 	// Negative zoom flips the axis.
 	Zoom Vec
 
+	// Angle is the rotation of the camera around the center in radians. The game space
+	// appears rotated the opposite way.
+	Angle float64
+
+	// Viewport is the rectangle of the underlying video output the camera shows the game in,
+	// e.g. a part of a split screen. The camera centers on it and clips everything it draws to
+	// it. Zero viewport means the whole output.
+	Viewport Rect
+
 	// VideoOutput is used to actually draw using a camera.
 	VideoOutput
+
+	inPass, passClipped bool // set by Begin
+}
+
+// viewport returns the rectangle of the underlying video output the camera shows the game in.
+func (c *Camera) viewport() Rect {
+	if c.Viewport == (Rect{}) {
+		return c.VideoOutput.OutputRect()
+	}
+	return c.Viewport
+}
+
+// clip clips drawing to the viewport and returns a function which removes the clip. Within
+// Begin and End, the viewport is clipped already.
+func (c *Camera) clip() func() {
+	if c.Viewport == (Rect{}) || c.inPass {
+		return func() {}
+	}
+	c.VideoOutput.PushClip(c.Viewport)
+	return c.VideoOutput.PopClip
+}
+
+// Begin starts drawing with the camera, clipping to its viewport once until End instead of in
+// every draw call. It's optional, but it saves changing the clip of the underlying video
+// output back and forth when drawing many things with one camera:
+//
+//	camera.Begin()
+//	defer camera.End()
+//
+// Clips and viewports pushed in between must be popped before End.
+func (c *Camera) Begin() {
+	if c.inPass {
+		panic("camera already begun, End wasn't called")
+	}
+	c.inPass, c.passClipped = true, c.Viewport != (Rect{})
+	if c.passClipped {
+		c.VideoOutput.PushClip(c.Viewport)
+	}
+}
+
+// End stops drawing with the camera started by Begin.
+func (c *Camera) End() {
+	if !c.inPass {
+		panic("camera not begun, nothing to end")
+	}
+	if c.passClipped {
+		c.VideoOutput.PopClip()
+	}
+	c.inPass, c.passClipped = false, false
+}
+
+// InViewport checks if a point of the underlying video output (e.g. the mouse position) is in
+// the viewport of the camera.
+func (c *Camera) InViewport(point Vec) bool {
+	return c.viewport().Contains(point)
+}
+
+// CameraAt returns the camera whose viewport contains a point of the video output (e.g. the
+// mouse position), so that the point can be unprojected with the right camera. If the
+// viewports overlap, the last camera wins, as it's usually drawn last. If no viewport contains
+// the point, it returns nil.
+func CameraAt(point Vec, cameras ...*Camera) *Camera {
+	for i := len(cameras) - 1; i >= 0; i-- {
+		if cameras[i].InViewport(point) {
+			return cameras[i]
+		}
+	}
+	return nil
+}
+
+// Matrix returns the transformation from game space to display space.
+func (c *Camera) Matrix() Matrix {
+	displayCenter := c.viewport().Center()
+	return IM.
+		Moved(c.Center.M(-1)).
+		Rotated(Vec{}, -c.Angle).
+		Scaled(Vec{}, c.Zoom).
+		Moved(displayCenter)
+}
+
+func (c *Camera) projection() projection {
+	return projection{c.Matrix(), c.VideoOutput}
 }
 
 // Project transfroms a point from game space to display space.
 func (c *Camera) Project(x, y float64) (float64, float64) {
-	displayCenter := c.VideoOutput.OutputRect().Center()
-	return (x-c.Center.X)*c.Zoom.X + displayCenter.X, (y-c.Center.Y)*c.Zoom.Y + displayCenter.Y
+	return c.Matrix().Project(Vec{X: x, Y: y}).XY()
 }
 
 // Unproject transfroms a point from display space to game space.
 func (c *Camera) Unproject(x, y float64) (float64, float64) {
-	displayCenter := c.VideoOutput.OutputRect().Center()
-	return (x-displayCenter.X)/c.Zoom.X + c.Center.X, (y-displayCenter.Y)/c.Zoom.Y + c.Center.Y
+	return c.Matrix().Unproject(Vec{X: x, Y: y}).XY()
 }
 
 // ProjectVec transforms a vector from game space to display space.
@@ -42,69 +131,158 @@ func (c *Camera) UnprojectVec(u Vec) (v Vec) {
 	return
 }
 
-// ProjectRect transforms a rectangle from game space to display space.
-func (c *Camera) ProjectRect(r1 Rect) (r2 Rect) {
-	if c.Zoom.X < 0 {
-		r1.X += r1.W
-		r1.W *= -1
-	}
-	if c.Zoom.Y < 0 {
-		r1.Y += r1.H
-		r1.H *= -1
-	}
-	r2.X, r2.Y = c.Project(r1.X, r1.Y)
-	r2.W, r2.H = r1.W*c.Zoom.X, r1.H*c.Zoom.Y
-	return
+// ProjectRect transforms a rectangle from game space to display space. If the camera is
+// rotated, it returns the smallest rectangle containing the transformed one.
+func (c *Camera) ProjectRect(r Rect) Rect {
+	return c.Matrix().projectBounds(r)
 }
 
-// UnprojectRect transfroms a rectangle from display space to game space.
-func (c *Camera) UnprojectRect(r1 Rect) (r2 Rect) {
-	if c.Zoom.X < 0 {
-		r1.X += r1.W
-		r1.W *= -1
-	}
-	if c.Zoom.Y < 0 {
-		r1.Y += r1.H
-		r1.H *= -1
-	}
-	r2.X, r2.Y = c.Unproject(r1.X, r1.Y)
-	r2.W, r2.H = r1.W/c.Zoom.X, r1.H/c.Zoom.Y
-	return
+// UnprojectRect transfroms a rectangle from display space to game space. If the camera is
+// rotated, it returns the smallest rectangle containing the transformed one.
+func (c *Camera) UnprojectRect(r Rect) Rect {
+	return c.Matrix().unprojectBounds(r)
 }
 
-// OutputRect returns an unprojected output rectangle of the underlying video output.
+// OutputRect returns an unprojected viewport of the camera.
 func (c *Camera) OutputRect() Rect {
-	return c.UnprojectRect(c.VideoOutput.OutputRect())
+	return c.UnprojectRect(c.viewport())
+}
+
+// PushClip restricts drawing to a rectangle projected with the camera. The camera still clips
+// to its own viewport too. Viewports of the video output aren't projected: PushViewport takes
+// a rectangle of the underlying video output, and the camera then centers on the viewport.
+func (c *Camera) PushClip(rect Rect) {
+	c.projection().PushClip(rect)
+}
+
+// Clear fills the viewport of the camera with one color.
+func (c *Camera) Clear(color Color) {
+	defer c.clip()()
+	c.VideoOutput.Clear(color)
 }
 
 // DrawPoint draws a point projected with the camera using the underlying video output.
 func (c *Camera) DrawPoint(point Vec, color Color) {
-	point = c.ProjectVec(point)
-	c.VideoOutput.DrawPoint(point, color)
+	defer c.clip()()
+	c.projection().DrawPoint(point, color)
 }
 
 // DrawLine draws a line projected with the camera using the underlying video output.
 func (c *Camera) DrawLine(a, b Vec, thickness float64, color Color) {
-	a, b = c.ProjectVec(a), c.ProjectVec(b)
-	c.VideoOutput.DrawLine(a, b, thickness, color)
+	defer c.clip()()
+	c.projection().DrawLine(a, b, thickness, color)
 }
 
 // DrawPolygon draws a polygon projected with the camera using the underlying video output.
 func (c *Camera) DrawPolygon(points []Vec, thickness float64, color Color) {
-	for i := range points {
-		points[i] = c.ProjectVec(points[i])
-	}
-	c.VideoOutput.DrawPolygon(points, thickness, color)
+	defer c.clip()()
+	c.projection().DrawPolygon(points, thickness, color)
+}
+
+// DrawPolyline draws a polyline projected with the camera using the underlying video output.
+// The stroke isn't scaled.
+func (c *Camera) DrawPolyline(points []Vec, closed bool, stroke Stroke, color Color) {
+	defer c.clip()()
+	c.projection().DrawPolyline(points, closed, stroke, color)
+}
+
+// DrawPath draws a path projected with the camera using the underlying video output. The
+// cached triangles of the path are projected too, so the path isn't triangulated again.
+func (c *Camera) DrawPath(path *Path, color Color) {
+	defer c.clip()()
+	c.projection().DrawPath(path, color)
 }
 
 // DrawRect draws a rectangle projected with the camera using the underlying video output.
+// If the camera is rotated, the rectangle is drawn as a polygon.
 func (c *Camera) DrawRect(rect Rect, thickness float64, color Color) {
-	rect = c.ProjectRect(rect)
-	c.VideoOutput.DrawRect(rect, thickness, color)
+	defer c.clip()()
+	c.projection().DrawRect(rect, thickness, color)
 }
 
 // DrawPicture draws a picture projected with the camera using the underlying video output.
+// Negative zoom flips the picture.
 func (c *Camera) DrawPicture(rect Rect, pic *Picture) {
-	rect = c.ProjectRect(rect)
-	c.VideoOutput.DrawPicture(rect, pic)
+	c.DrawPictureEx(rect, pic, PictureOptions{Pivot: rect.Size().D(2)})
+}
+
+// DrawPictureEx draws a transformed picture projected with the camera using the underlying
+// video output. Negative zoom flips the picture and mirrors its rotation. If the camera is
+// rotated, the picture is drawn as a mesh.
+func (c *Camera) DrawPictureEx(rect Rect, pic *Picture, opts PictureOptions) {
+	defer c.clip()()
+	c.projection().DrawPictureEx(rect, pic, opts)
+}
+
+// DrawBatch draws a batch projected with the camera using the underlying video output.
+// If the camera is rotated, the sprites are drawn as meshes.
+func (c *Camera) DrawBatch(batch *Batch) {
+	defer c.clip()()
+	c.projection().DrawBatch(batch)
+}
+
+// DrawText draws text projected with the camera using the underlying video output.
+// The text is scaled by the zoom of the camera.
+func (c *Camera) DrawText(pos Vec, font Font, text string, color Color) {
+	defer c.clip()()
+	c.projection().DrawText(pos, font, text, color)
+}
+
+// DrawCircle draws a circle projected with the camera using the underlying video output.
+// If the zoom differs on each axis, the circle is drawn as an ellipse.
+func (c *Camera) DrawCircle(center Vec, radius, thickness float64, color Color) {
+	defer c.clip()()
+	c.projection().DrawCircle(center, radius, thickness, color)
+}
+
+// DrawEllipse draws an ellipse projected with the camera using the underlying video output.
+func (c *Camera) DrawEllipse(center, radius Vec, thickness float64, color Color) {
+	defer c.clip()()
+	c.projection().DrawEllipse(center, radius, thickness, color)
+}
+
+// DrawArc draws an arc projected with the camera using the underlying video output.
+func (c *Camera) DrawArc(center, radius Vec, start, end, thickness float64, color Color) {
+	defer c.clip()()
+	c.projection().DrawArc(center, radius, start, end, thickness, color)
+}
+
+// DrawPie draws a pie slice projected with the camera using the underlying video output.
+func (c *Camera) DrawPie(center, radius Vec, start, end, thickness float64, color Color) {
+	defer c.clip()()
+	c.projection().DrawPie(center, radius, start, end, thickness, color)
+}
+
+// DrawRoundedRect draws a rounded rectangle projected with the camera using the underlying
+// video output. If the zoom differs on each axis, the corners are drawn as parts of ellipses.
+func (c *Camera) DrawRoundedRect(rect Rect, radius, thickness float64, color Color) {
+	defer c.clip()()
+	c.projection().DrawRoundedRect(rect, radius, thickness, color)
+}
+
+// DrawBezier draws a Bézier curve projected with the camera using the underlying video
+// output.
+func (c *Camera) DrawBezier(points []Vec, thickness float64, color Color) {
+	defer c.clip()()
+	c.projection().DrawBezier(points, thickness, color)
+}
+
+// DrawGradientPolygon draws a polygon filled with a gradient projected with the camera using
+// the underlying video output.
+func (c *Camera) DrawGradientPolygon(points []Vec, gradient Gradient) {
+	defer c.clip()()
+	c.projection().DrawGradientPolygon(points, gradient)
+}
+
+// DrawGradientRect draws a rectangle filled with a gradient projected with the camera using
+// the underlying video output.
+func (c *Camera) DrawGradientRect(rect Rect, gradient Gradient) {
+	defer c.clip()()
+	c.projection().DrawGradientRect(rect, gradient)
+}
+
+// DrawMesh draws a mesh projected with the camera using the underlying video output.
+func (c *Camera) DrawMesh(mesh *Mesh) {
+	defer c.clip()()
+	c.projection().DrawMesh(mesh)
 }
@@ -0,0 +1,44 @@
+package gogame
+
+import "testing"
+
+// clipCounter is a video output counting the clips pushed to it. Other methods panic.
+type clipCounter struct {
+	VideoOutput
+	pushed, depth int
+}
+
+func (c *clipCounter) PushClip(rect Rect) { c.pushed, c.depth = c.pushed+1, c.depth+1 }
+func (c *clipCounter) PopClip()           { c.depth-- }
+func (c *clipCounter) Clear(color Color)  {}
+
+func TestCameraBegin(t *testing.T) {
+	tests := []struct {
+		name     string
+		viewport Rect
+		begin    bool
+		want     int
+	}{
+		{"whole output", Rect{}, false, 0},
+		{"viewport", Rect{W: 10, H: 10}, false, 3},
+		{"viewport in a pass", Rect{W: 10, H: 10}, true, 1},
+		{"whole output in a pass", Rect{}, true, 0},
+	}
+	for _, test := range tests {
+		output := &clipCounter{}
+		camera := &Camera{Zoom: Vec{X: 1, Y: 1}, Viewport: test.viewport, VideoOutput: output}
+		if test.begin {
+			camera.Begin()
+		}
+		for i := 0; i < 3; i++ {
+			camera.Clear(Color{})
+		}
+		if test.begin {
+			camera.End()
+		}
+		if output.pushed != test.want || output.depth != 0 {
+			t.Errorf("%s: got %d clips pushed and %d left, want %d and none", test.name,
+				output.pushed, output.depth, test.want)
+		}
+	}
+}
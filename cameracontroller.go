@@ -0,0 +1,236 @@
+package gogame
+
+import "math"
+
+// NewCameraController creates a controller driving a camera from its current center and zoom.
+func NewCameraController(camera *Camera) *CameraController {
+	return &CameraController{
+		Camera:   camera,
+		focus:    camera.Center,
+		zoom:     camera.Zoom,
+		zoomGoal: camera.Zoom,
+	}
+}
+
+// CameraController moves a camera the way games usually do: it follows a target smoothly,
+// keeps the view within the bounds of the world, zooms smoothly and shakes. Call Update once
+// per frame, before drawing.
+//
+// The controller owns the Center and Zoom of the camera, it overwrites them in every Update.
+// The shake is added to them (and to the Angle), so Project and Unproject of the camera map
+// to what's on the screen.
+// Camera needs to be set for a camera controller to work properly.
+type CameraController struct {
+	// Camera is the camera driven by the controller.
+	Camera *Camera
+
+	// Follow turns following the target on.
+	Follow bool
+
+	// Target is the point in game space the camera follows, e.g. the position of the player.
+	// Update it every frame.
+	Target Vec
+
+	// DeadZone is the size (in pixels of the display) of a rectangle in the middle of the view,
+	// within which the target can move without moving the camera.
+	DeadZone Vec
+
+	// LookAhead moves the camera ahead of a moving target, by the distance the target moves
+	// in LookAhead seconds.
+	LookAhead float64
+
+	// Smoothing is how slowly the camera catches up with the target: the time (in seconds) in
+	// which it covers about two thirds of the distance. 0 means immediately.
+	Smoothing float64
+
+	// ZoomSmoothing is how slowly the zoom changes, just like Smoothing.
+	ZoomSmoothing float64
+
+	// Bounds is a rectangle of game space the view never leaves. If the view is bigger than
+	// the bounds, it's centered on them. Zero bounds means no bounds.
+	Bounds Rect
+
+	// MaxShake is how far (in pixels of the display) the view moves at most when shaking, and
+	// MaxShakeAngle how much (in radians) it rotates at most.
+	MaxShake      float64
+	MaxShakeAngle float64
+
+	// ShakeFrequency is how many times per second the shake changes direction. 0 means 15.
+	ShakeFrequency float64
+
+	// TraumaDecay is how much trauma goes away every second. 0 means 1.
+	TraumaDecay float64
+
+	focus      Vec // the center without the shake
+	zoom       Vec
+	zoomGoal   Vec
+	zoomPoint  Vec
+	lastTarget Vec
+	hasTarget  bool
+	trauma     float64
+	time       float64
+	shakeAngle float64 // added to the angle of the camera by the last Update
+}
+
+// ZoomTo starts zooming smoothly to a zoom, so that a point in game space stays at the same
+// place on the display, e.g. the point under the mouse cursor:
+//
+//	ctrl.ZoomTo(zoom, camera.UnprojectVec(in.MousePosition()))
+//
+// If the camera follows a target, the target wins over the point.
+func (c *CameraController) ZoomTo(zoom, point Vec) {
+	c.zoomGoal = zoom
+	c.zoomPoint = point
+}
+
+// Zoom returns the zoom the camera is zooming to.
+func (c *CameraController) Zoom() Vec {
+	return c.zoomGoal
+}
+
+// JumpTo moves the camera to a center immediately, without smoothing, e.g. when the player
+// respawns or the level changes. If the camera follows a target, it follows it from there on,
+// so jump to the target to skip catching up with it.
+func (c *CameraController) JumpTo(center Vec) {
+	c.focus = center
+	c.hasTarget = false // the jump isn't a movement of the target to look ahead of
+	c.Camera.Center = center
+}
+
+// Shake adds trauma, which makes the camera shake. Trauma is between 0 and 1, and the shake
+// grows with its square, so small hits barely shake and big ones shake a lot. Trauma goes away
+// over time.
+func (c *CameraController) Shake(trauma float64) {
+	c.trauma = clamp(c.trauma+trauma, 0, 1)
+}
+
+// Trauma returns the current trauma.
+func (c *CameraController) Trauma() float64 {
+	return c.trauma
+}
+
+// Update moves the camera. Dt is the time that passed since the previous call to Update.
+func (c *CameraController) Update(dt float64) {
+	c.time += dt
+
+	c.updateZoom(dt)
+	if c.Follow {
+		c.updateFollow(dt)
+	}
+	c.Camera.Center, c.Camera.Zoom = c.focus, c.zoom
+	c.clampToBounds()
+	c.updateShake(dt)
+}
+
+// smoothing returns which part of the remaining distance to cover in dt.
+func smoothing(dt, duration float64) float64 {
+	if duration <= 0 {
+		return 1
+	}
+	return 1 - math.Exp(-dt/duration)
+}
+
+func (c *CameraController) updateZoom(dt float64) {
+	if c.zoom == c.zoomGoal {
+		return
+	}
+	old := c.zoom
+	c.zoom = c.zoom.A(c.zoomGoal.S(c.zoom).M(smoothing(dt, c.ZoomSmoothing)))
+	if math.Abs(c.zoomGoal.X-c.zoom.X) < 1e-4 && math.Abs(c.zoomGoal.Y-c.zoom.Y) < 1e-4 {
+		c.zoom = c.zoomGoal
+	}
+
+	// keep the zoom point at the same place on the display; the zoom scales the axes of the
+	// display, so the offset is scaled in the rotated coordinates of the camera
+	if c.zoom.X != 0 && c.zoom.Y != 0 {
+		angle := c.Camera.Angle - c.shakeAngle
+		offset := c.zoomPoint.S(c.focus).Rotated(-angle)
+		offset = Vec{X: offset.X * old.X / c.zoom.X, Y: offset.Y * old.Y / c.zoom.Y}
+		c.focus = c.zoomPoint.S(offset.Rotated(angle))
+	}
+}
+
+func (c *CameraController) updateFollow(dt float64) {
+	var velocity Vec
+	if c.hasTarget && dt > 0 {
+		velocity = c.Target.S(c.lastTarget).D(dt)
+	}
+	c.lastTarget, c.hasTarget = c.Target, true
+
+	goal := c.Target.A(velocity.M(c.LookAhead))
+
+	// within the dead zone, the camera stays where it is
+	if c.zoom.X != 0 && c.zoom.Y != 0 {
+		half := Vec{X: math.Abs(c.DeadZone.X / c.zoom.X), Y: math.Abs(c.DeadZone.Y / c.zoom.Y)}.D(2)
+		d := goal.S(c.focus)
+		goal = c.focus.A(Vec{
+			X: math.Max(d.X-half.X, 0) + math.Min(d.X+half.X, 0),
+			Y: math.Max(d.Y-half.Y, 0) + math.Min(d.Y+half.Y, 0),
+		})
+	}
+
+	c.focus = c.focus.A(goal.S(c.focus).M(smoothing(dt, c.Smoothing)))
+}
+
+// clampToBounds moves the camera so that its view stays within the bounds.
+func (c *CameraController) clampToBounds() {
+	if c.Bounds == (Rect{}) || c.zoom.X == 0 || c.zoom.Y == 0 {
+		return
+	}
+	view := c.Camera.OutputRect()
+	clampAxis := func(center, viewMin, viewSize, min, size float64) float64 {
+		if viewSize >= size {
+			return min + size/2
+		}
+		if viewMin < min {
+			return center + min - viewMin
+		}
+		if viewMin+viewSize > min+size {
+			return center + min + size - viewMin - viewSize
+		}
+		return center
+	}
+	c.focus = Vec{
+		X: clampAxis(c.focus.X, view.X, view.W, c.Bounds.X, c.Bounds.W),
+		Y: clampAxis(c.focus.Y, view.Y, view.H, c.Bounds.Y, c.Bounds.H),
+	}
+	c.Camera.Center = c.focus
+}
+
+func (c *CameraController) updateShake(dt float64) {
+	decay := c.TraumaDecay
+	if decay == 0 {
+		decay = 1
+	}
+	frequency := c.ShakeFrequency
+	if frequency == 0 {
+		frequency = 15
+	}
+	c.trauma = math.Max(c.trauma-decay*dt, 0)
+	shake := c.trauma * c.trauma
+
+	t := c.time * frequency
+	offset := Vec{X: smoothNoise(0, t), Y: smoothNoise(1, t)}.M(shake * c.MaxShake)
+	if c.zoom.X != 0 && c.zoom.Y != 0 {
+		// the offset is on the display, so it doesn't depend on the zoom
+		offset = Vec{X: offset.X / c.zoom.X, Y: offset.Y / c.zoom.Y}.Rotated(c.Camera.Angle)
+		c.Camera.Center = c.Camera.Center.A(offset)
+	}
+
+	angle := smoothNoise(2, t) * shake * c.MaxShakeAngle
+	c.Camera.Angle += angle - c.shakeAngle
+	c.shakeAngle = angle
+}
+
+// smoothNoise returns a value between -1 and 1 changing smoothly and randomly with t, about
+// once per unit of t. Different seeds give independent noise.
+func smoothNoise(seed, t float64) float64 {
+	random := func(i float64) float64 {
+		_, frac := math.Modf(math.Abs(math.Sin(i*12.9898+seed*78.233) * 43758.5453))
+		return frac*2 - 1
+	}
+	i := math.Floor(t)
+	f := t - i
+	f = f * f * (3 - 2*f) // smoothstep
+	return random(i)*(1-f) + random(i+1)*f
+}
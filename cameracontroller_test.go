@@ -0,0 +1,61 @@
+package gogame
+
+import (
+	"math"
+	"testing"
+)
+
+func TestCameraControllerZoomTo(t *testing.T) {
+	tests := []struct {
+		name  string
+		angle float64
+		zoom  Vec
+		goal  Vec
+	}{
+		{"same on both axes", 0, Vec{X: 1, Y: 1}, Vec{X: 2, Y: 2}},
+		{"different on each axis", 0, Vec{X: 1, Y: 2}, Vec{X: 3, Y: 1}},
+		{"rotated", math.Pi / 3, Vec{X: 1, Y: 1}, Vec{X: 2, Y: 2}},
+		{"rotated and different on each axis", math.Pi / 3, Vec{X: 1, Y: 2}, Vec{X: 3, Y: 1}},
+		{"rotated and flipped", -1, Vec{X: -1, Y: 1}, Vec{X: -2, Y: 3}},
+	}
+	for _, test := range tests {
+		camera := &Camera{
+			Center:   Vec{X: 10, Y: 20},
+			Zoom:     test.zoom,
+			Angle:    test.angle,
+			Viewport: Rect{W: 640, H: 480},
+		}
+		ctrl := NewCameraController(camera)
+		ctrl.ZoomSmoothing = 0.5
+
+		point := Vec{X: 50, Y: -30}
+		want := camera.ProjectVec(point)
+		ctrl.ZoomTo(test.goal, point)
+		for i := 0; i < 3; i++ {
+			ctrl.Update(0.1)
+			if got := camera.ProjectVec(point); !vecsClose(got, want) {
+				t.Errorf("%s: got the zoom point at %v, want %v", test.name, got, want)
+				break
+			}
+		}
+	}
+}
+
+func TestCameraControllerJumpTo(t *testing.T) {
+	camera := &Camera{Zoom: Vec{X: 1, Y: 1}, Viewport: Rect{W: 640, H: 480}}
+	ctrl := NewCameraController(camera)
+	ctrl.Follow, ctrl.Smoothing, ctrl.LookAhead = true, 1, 1
+
+	ctrl.Update(0.1)
+	ctrl.Target = Vec{X: 1000, Y: 0}
+	ctrl.JumpTo(ctrl.Target)
+	if camera.Center != ctrl.Target {
+		t.Errorf("jump: got the camera at %v, want %v", camera.Center, ctrl.Target)
+	}
+
+	// the jump doesn't count as a movement of the target, so there's nothing to look ahead of
+	ctrl.Update(0.1)
+	if camera.Center != ctrl.Target {
+		t.Errorf("update after a jump: got the camera at %v, want %v", camera.Center, ctrl.Target)
+	}
+}
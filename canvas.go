@@ -5,18 +5,19 @@ import (
 	"github.com/veandco/go-sdl2/sdl"
 )
 
-// NewCanvas creates an empty canvas with the specified width and height.
+// NewCanvas creates an empty, fully transparent canvas with the specified width and height.
 func NewCanvas(width, height int) *Canvas {
 	var err error
 	canvas := &Canvas{
 		rendererOutput: rendererOutput{
-			textures: make(map[*sdl.Surface]*sdl.Texture),
-			mask:     Color{1, 1, 1, 1},
+			cache:         newTextureCache(),
+			mask:          Color{1, 1, 1, 1},
+			premultiplied: true,
+			blendMode:     BlendAlpha,
 		},
 	}
 
-	// no staticSurface flag, this suface is dynamic
-	canvas.surface, err = sdl.CreateRGBSurface(0, int32(width), int32(height), 32, 0, 0, 0, 0)
+	canvas.surface, err = newCanvasSurface(width, height)
 	if err != nil {
 		panic(errors.Wrap(err, "failed to create canvas"))
 	}
@@ -30,9 +31,32 @@ func NewCanvas(width, height int) *Canvas {
 	return canvas
 }
 
+// newCanvasSurface creates a transparent RGBA surface for a canvas. It's static, canvases count
+// its versions as they draw, so that its textures are only created again after drawing.
+func newCanvasSurface(width, height int) (*sdl.Surface, error) {
+	surface, err := sdl.CreateRGBSurfaceWithFormat(
+		0,
+		int32(width),
+		int32(height),
+		32,
+		uint32(sdl.PIXELFORMAT_RGBA32),
+	)
+	if err != nil {
+		return nil, err
+	}
+	surfaceFlags[surface] |= staticSurface | premultipliedSurface
+	return surface, nil
+}
+
 // Canvas is an offscreen picture that you can draw on.
+//
+// A canvas has an alpha channel and starts fully transparent, so it can be used as a layer
+// drawn over the scene. Clear(Color{}) makes it fully transparent again. The picture of a
+// canvas blends like any other picture with straight colors. With BlendAlpha, transparent
+// parts of it composite correctly: drawing onto a transparent canvas and then drawing the
+// canvas gives the same result as drawing directly. Other blend modes don't compose like that,
+// e.g. BlendAdditive on a canvas adds to its transparency, not to what's drawn under it.
 type Canvas struct {
-	surface *sdl.Surface
 	rendererOutput
 }
 
@@ -45,8 +69,12 @@ func (c *Canvas) Picture() *Picture {
 	}
 }
 
-// OutputRect returns a (0, 0, w, h) rectangle, where w, h is the width and height of the canvas.
+// OutputRect returns a (0, 0, w, h) rectangle, where w, h is the width and height of the canvas,
+// or of the current viewport.
 func (c *Canvas) OutputRect() Rect {
+	if rect, ok := c.viewportRect(); ok {
+		return rect
+	}
 	return Rect{
 		X: 0,
 		Y: 0,
@@ -54,3 +82,177 @@ func (c *Canvas) OutputRect() Rect {
 		H: float64(c.surface.H),
 	}
 }
+
+// Resize changes the size of the canvas. The contents are kept at the top-left corner, cut if
+// the canvas shrinks and transparent around if it grows. Clips and viewports are removed.
+// Pictures of the canvas returned before Resize must not be used anymore, call Picture again.
+func (c *Canvas) Resize(width, height int) {
+	surface, err := newCanvasSurface(width, height)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to resize canvas"))
+	}
+	renderer, err := sdl.CreateSoftwareRenderer(surface)
+	if err != nil {
+		surface.Free()
+		panic(errors.Wrap(err, "failed to resize canvas"))
+	}
+
+	c.surface.SetBlendMode(sdl.BLENDMODE_NONE)
+	c.surface.Blit(nil, surface, nil)
+
+	c.destroy()
+	c.cache = newTextureCache()
+	c.surface, c.renderer = surface, renderer
+	c.clips = nil
+	c.SetBlendMode(c.blendMode)
+}
+
+// Destroy frees the pixels of the canvas. The canvas and its pictures must not be used
+// afterwards.
+func (c *Canvas) Destroy() {
+	c.destroy()
+	c.surface, c.renderer, c.cache = nil, nil, nil
+}
+
+func (c *Canvas) destroy() {
+	// textures belong to the renderer, they can't outlive it
+	c.cache.destroy()
+	delete(boundOutputs, c.renderer)
+	c.renderer.Destroy()
+	freeSurface(c.surface)
+}
+
+func (o *rendererOutput) NewTargetCanvas(width, height int) *TargetCanvas {
+	canvas := &TargetCanvas{
+		rendererOutput: rendererOutput{
+			renderer:      o.renderer,
+			cache:         o.cache,
+			mask:          Color{1, 1, 1, 1},
+			premultiplied: true,
+			blendMode:     BlendAlpha,
+		},
+	}
+
+	var err error
+	canvas.target, canvas.surface, err = canvas.newTarget(width, height)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to create target canvas"))
+	}
+
+	// the contents of a new texture are undefined
+	canvas.Clear(Color{})
+
+	return canvas
+}
+
+// newTarget creates a target texture and a surface for reading it back.
+func (c *TargetCanvas) newTarget(width, height int) (*sdl.Texture, *sdl.Surface, error) {
+	target, err := c.renderer.CreateTexture(
+		sdl.PIXELFORMAT_ARGB8888,
+		sdl.TEXTUREACCESS_TARGET,
+		int32(width),
+		int32(height),
+	)
+	if err != nil {
+		return nil, nil, err
+	}
+
+	// only for reading the texture back, it's static until then
+	surface, err := newCanvasSurface(width, height)
+	if err != nil {
+		target.Destroy()
+		return nil, nil, err
+	}
+	return target, surface, nil
+}
+
+// TargetCanvas is an offscreen picture that you can draw on, just like Canvas, but drawn by the
+// same renderer as the video output which created it. Canvases created by the window are drawn
+// by the GPU and their pictures are drawn onto the window without copying any pixels, which
+// makes them fast for things like lighting buffers and minimaps.
+//
+// The pixels only get copied (read back from the GPU) when the picture is drawn onto another
+// output, such as a Canvas, or with a blend mode other than BlendAlpha and BlendAdditive, or
+// copied with Copy. A target canvas can only be used while its
+// video output exists.
+type TargetCanvas struct {
+	rendererOutput
+}
+
+// Picture returns a pointer to the underlying picture of the canvas. The picture will change
+// according to the drawing operations on the canvas. Don't draw the picture onto its own
+// canvas.
+func (c *TargetCanvas) Picture() *Picture {
+	return &Picture{
+		surface: c.surface,
+		rect:    sdl.Rect{X: 0, Y: 0, W: c.surface.W, H: c.surface.H},
+		canvas:  c,
+	}
+}
+
+// OutputRect returns a (0, 0, w, h) rectangle, where w, h is the width and height of the canvas,
+// or of the current viewport.
+func (c *TargetCanvas) OutputRect() Rect {
+	if rect, ok := c.viewportRect(); ok {
+		return rect
+	}
+	return Rect{
+		X: 0,
+		Y: 0,
+		W: float64(c.surface.W),
+		H: float64(c.surface.H),
+	}
+}
+
+// Resize changes the size of the canvas, just like Canvas.Resize.
+func (c *TargetCanvas) Resize(width, height int) {
+	target, surface, err := c.newTarget(width, height)
+	if err != nil {
+		panic(errors.Wrap(err, "failed to resize target canvas"))
+	}
+
+	old := c.target
+	c.target = target
+	c.clips = nil
+	delete(boundOutputs, c.renderer) // bind the new target
+	c.Clear(Color{})
+	old.SetBlendMode(sdl.BLENDMODE_NONE)
+	rect := sdl.Rect{X: 0, Y: 0, W: c.surface.W, H: c.surface.H}
+	c.renderer.Copy(old, nil, &rect)
+	old.Destroy()
+
+	freeSurface(c.surface)
+	c.surface = surface
+}
+
+// Destroy frees the target texture and the pixels of the canvas. The canvas and its pictures
+// must not be used afterwards. Destroy target canvases which aren't needed anymore, their
+// textures take up memory of the GPU.
+func (c *TargetCanvas) Destroy() {
+	if boundOutputs[c.renderer] == &c.rendererOutput {
+		// SDL draws to the default target after destroying the current one, the output using
+		// it needs to be bound again
+		delete(boundOutputs, c.renderer)
+	}
+	c.target.Destroy()
+	freeSurface(c.surface)
+	c.target, c.surface = nil, nil
+}
+
+// readBack copies the pixels of the target texture into the surface, unless nothing was drawn
+// since the last time.
+func (c *TargetCanvas) readBack() {
+	if !c.dirty {
+		return
+	}
+	c.bind()
+	// read the whole target, not only the viewport
+	c.renderer.SetViewport(nil)
+	err := c.renderer.ReadPixels(nil, c.surface.Format.Format, c.surface.Data(), int(c.surface.Pitch))
+	c.applyClip()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to read target canvas"))
+	}
+	surfaceVersions[c.surface]++
+	c.dirty = false
+}
@@ -19,6 +19,12 @@ func (c Color) Mul(d Color) Color {
 	}
 }
 
+// premultiplied returns the color with the color channels multiplied by alpha, which is how
+// canvases store colors.
+func (c Color) premultiplied() Color {
+	return Color{R: c.R * c.A, G: c.G * c.A, B: c.B * c.A, A: c.A}
+}
+
 // Colors defines some common colors.
 var Colors = map[string]Color{
 	"black":   {0.0, 0.0, 0.0, 1.0},
@@ -0,0 +1,56 @@
+package gogame
+
+// EventKind specifies what happened in an input event.
+type EventKind int
+
+// Enumeration of all input event kinds.
+const (
+	EventKeyDown EventKind = iota
+	EventKeyUp
+	EventMouseDown
+	EventMouseUp
+	EventMouseMove
+	EventMouseWheel
+	EventTouchBegin
+	EventTouchMove
+	EventTouchEnd
+	EventDrop
+)
+
+// Event is a single input event. Only the fields relevant to the event's kind are set.
+type Event struct {
+	Kind EventKind
+
+	// Time is when the event happened, in seconds since Gogame was initialized.
+	Time float64
+
+	// Key is the key of a keyboard event.
+	Key int
+
+	// Scancode is the physical position of the key of a keyboard event.
+	Scancode int
+
+	// Mods are the modifiers active during a keyboard event (a combination of Mod* flags).
+	Mods int
+
+	// Repeat is true if a key down event was generated by holding the key down.
+	Repeat bool
+
+	// Button is the mouse button of a mouse button event.
+	Button int
+
+	// Pos is the position of a mouse, touch or drop event relative to the window.
+	Pos Vec
+
+	// Delta is the movement of a mouse or touch event, or the scroll of a mouse wheel event.
+	Delta Vec
+
+	// TouchID identifies the finger of a touch event.
+	TouchID int64
+
+	// Pressure is the pressure of the finger of a touch event, between 0 and 1.
+	Pressure float64
+
+	// Drop is the dropped file or text of a drop event.
+	Drop Drop
+}
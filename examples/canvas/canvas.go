@@ -15,7 +15,11 @@ func main() {
 	}
 
 	canvas := gogame.NewCanvas(cfg.Width, cfg.Height)
-	canvas.DrawPolygon([]gogame.Vec{{200, 200}, {200, 600}, {900, 400}}, 0, gogame.Colors["grey"])
+	canvas.DrawPolygon(
+		[]gogame.Vec{{X: 200, Y: 200}, {X: 200, Y: 600}, {X: 900, Y: 400}},
+		0,
+		gogame.Colors["grey"],
+	)
 	canvas.Clear(gogame.Colors["white"])
 
 	picture := canvas.Picture().Copy()
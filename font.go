@@ -0,0 +1,246 @@
+package gogame
+
+import (
+	"fmt"
+
+	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
+)
+
+// Font is a source of glyphs for drawing text.
+type Font interface {
+	// Glyph returns the picture of a rune and a rectangle to draw it onto. The rectangle is
+	// relative to the pen position, which is at the top-left of the rune's line. Advance is
+	// how much the pen moves to the right after the rune. If the rune is invisible (e.g. a
+	// space), pic is nil.
+	Glyph(r rune) (pic *Picture, rect Rect, advance float64)
+
+	// Kerning returns how much the pen should move between two consecutive runes (usually
+	// a small negative number).
+	Kerning(a, b rune) float64
+
+	// LineHeight returns the distance between the tops of two consecutive lines.
+	LineHeight() float64
+}
+
+// forEachGlyph lays out text line by line, starting with the pen at pos, and calls draw for each
+// visible rune.
+func forEachGlyph(pos Vec, font Font, text string, draw func(rect Rect, pic *Picture)) {
+	pen := pos
+	var prev rune
+	for _, r := range text {
+		if r == '\n' {
+			pen.X = pos.X
+			pen.Y += font.LineHeight()
+			prev = 0
+			continue
+		}
+		if prev != 0 {
+			pen.X += font.Kerning(prev, r)
+		}
+		pic, rect, advance := font.Glyph(r)
+		if pic != nil {
+			draw(rect.MovedBy(pen), pic)
+		}
+		pen.X += advance
+		prev = r
+	}
+}
+
+// scaledFont scales the glyphs of a font, e.g. by the zoom of a camera. The scale must be
+// positive, mirrored text has mirrored glyphs, which fonts can't express.
+type scaledFont struct {
+	Font
+	scale Vec
+}
+
+func (f scaledFont) Glyph(r rune) (pic *Picture, rect Rect, advance float64) {
+	pic, rect, advance = f.Font.Glyph(r)
+	rect = Rect{
+		X: rect.X * f.scale.X,
+		Y: rect.Y * f.scale.Y,
+		W: rect.W * f.scale.X,
+		H: rect.H * f.scale.Y,
+	}
+	return pic, rect, advance * f.scale.X
+}
+
+func (f scaledFont) Kerning(a, b rune) float64 {
+	return f.Font.Kerning(a, b) * f.scale.X
+}
+
+func (f scaledFont) LineHeight() float64 {
+	return f.Font.LineHeight() * f.scale.Y
+}
+
+type trueTypeFontKey struct {
+	path string
+	size int
+}
+
+var trueTypeFonts = make(map[trueTypeFontKey]*TrueTypeFont)
+
+// LoadFont loads a TrueType font from a file stored at the specified path. Size is the height
+// of the font in points. Loading the same font with the same size again returns the same font,
+// so that the glyphs don't need to be rasterized again, until the font is closed.
+// If the loading fails, an error is returned.
+func LoadFont(path string, size int) (*TrueTypeFont, error) {
+	key := trueTypeFontKey{path, size}
+	if font := trueTypeFonts[key]; font != nil {
+		return font, nil
+	}
+
+	ttfFont, err := ttf.OpenFont(path, size)
+	if err != nil {
+		return nil, fmt.Errorf("failed to load font: %s", path)
+	}
+
+	font := &TrueTypeFont{
+		key:    key,
+		font:   ttfFont,
+		glyphs: make(map[rune]trueTypeGlyph),
+	}
+	err = font.addPage()
+	if err != nil {
+		ttfFont.Close()
+		return nil, fmt.Errorf("failed to load font: %s", path)
+	}
+
+	// printable ASCII is needed almost always, so rasterize it right away
+	for r := ' '; r <= '~'; r++ {
+		font.rasterize(r)
+	}
+
+	trueTypeFonts[key] = font
+	return font, nil
+}
+
+// TrueTypeFont is a TrueType font of a fixed size. Its glyphs are rasterized once into glyph
+// atlas pictures, so drawing text is as fast as drawing pictures.
+type TrueTypeFont struct {
+	key    trueTypeFontKey
+	font   *ttf.Font
+	pages  []*sdl.Surface // glyph atlases, new glyphs go into the last one
+	glyphs map[rune]trueTypeGlyph
+	penX   int32
+	penY   int32
+}
+
+type trueTypeGlyph struct {
+	page    int
+	rect    sdl.Rect // within the page, zero size if invisible
+	advance float64
+}
+
+const atlasSize = 512
+
+// Glyph returns the picture of a rune from a glyph atlas. Runes which have not been needed
+// before are rasterized into an atlas first.
+func (f *TrueTypeFont) Glyph(r rune) (pic *Picture, rect Rect, advance float64) {
+	glyph, ok := f.glyphs[r]
+	if !ok {
+		glyph = f.rasterize(r)
+	}
+	if glyph.rect.W == 0 {
+		return nil, Rect{}, glyph.advance
+	}
+	pic = &Picture{surface: f.pages[glyph.page], rect: glyph.rect}
+	rect = Rect{X: 0, Y: 0, W: float64(glyph.rect.W), H: float64(glyph.rect.H)}
+	return pic, rect, glyph.advance
+}
+
+// Kerning always returns 0, TrueType fonts are drawn without kerning.
+func (f *TrueTypeFont) Kerning(a, b rune) float64 {
+	return 0
+}
+
+// LineHeight returns the recommended distance between two lines of the font.
+func (f *TrueTypeFont) LineHeight() float64 {
+	return float64(f.font.LineSkip())
+}
+
+func (f *TrueTypeFont) rasterize(r rune) trueTypeGlyph {
+	var glyph trueTypeGlyph
+	if metrics, err := f.font.GlyphMetrics(r); err == nil {
+		glyph.advance = float64(metrics.Advance)
+	}
+
+	surface, err := f.font.RenderUTF8Blended(string(r), sdl.Color{R: 255, G: 255, B: 255, A: 255})
+	if err != nil { // nothing to render, e.g. a zero-width rune
+		f.glyphs[r] = glyph
+		return glyph
+	}
+	defer surface.Free()
+
+	page := f.pages[len(f.pages)-1]
+	if f.penX+surface.W > page.W {
+		f.penX = 0
+		f.penY += int32(f.font.Height())
+	}
+	if f.penY+surface.H > page.H {
+		if err := f.addPage(); err != nil {
+			panic(fmt.Errorf("failed to add a glyph atlas: %s", err))
+		}
+		page = f.pages[len(f.pages)-1]
+	}
+
+	glyph.page = len(f.pages) - 1
+	glyph.rect = sdl.Rect{X: f.penX, Y: f.penY, W: surface.W, H: surface.H}
+	surface.SetBlendMode(sdl.BLENDMODE_NONE)
+	dst := glyph.rect
+	surface.Blit(nil, page, &dst)
+	surfaceVersions[page]++
+	f.penX += surface.W
+
+	f.glyphs[r] = glyph
+	return glyph
+}
+
+// addPage adds a glyph atlas and moves the pen to its top-left corner. Full pages are kept,
+// pictures of their glyphs stay valid until the font is closed.
+func (f *TrueTypeFont) addPage() error {
+	size := atlasSize
+	if 4*f.font.Height() > size { // fit even the widest glyphs of huge fonts
+		size = 4 * f.font.Height()
+	}
+	page, err := newAtlasSurface(size, size)
+	if err != nil {
+		return err
+	}
+	f.pages = append(f.pages, page)
+	f.penX, f.penY = 0, 0
+	return nil
+}
+
+// Close frees the font and its glyph atlases, including their textures in all outputs. Neither
+// the font nor the pictures of its glyphs can be used after closing it. LoadFont loads the font
+// again.
+func (f *TrueTypeFont) Close() {
+	for _, page := range f.pages {
+		freeSurface(page)
+	}
+	f.pages = nil
+	f.font.Close()
+	if trueTypeFonts[f.key] == f {
+		delete(trueTypeFonts, f.key)
+	}
+}
+
+// newAtlasSurface creates a transparent static RGBA surface for a glyph atlas.
+func newAtlasSurface(w, h int) (*sdl.Surface, error) {
+	surface, err := sdl.CreateRGBSurface(
+		0,
+		int32(w),
+		int32(h),
+		32,
+		0x000000ff,
+		0x0000ff00,
+		0x00ff0000,
+		0xff000000,
+	)
+	if err != nil {
+		return nil, err
+	}
+	surfaceFlags[surface] |= staticSurface
+	return surface, nil
+}
@@ -117,6 +117,11 @@ func (r Rect) Resized(size Vec) Rect {
 	}
 }
 
+// Contains checks if a point is inside of a rectangle.
+func (r Rect) Contains(u Vec) bool {
+	return u.X >= r.X && u.X < r.X+r.W && u.Y >= r.Y && u.Y < r.Y+r.H
+}
+
 // Overlap returns the overlap vector of r1 and r2. If there's no overlap the result is {0, 0}.
 // Otherwise, the result represents, how much r1 needs to be moved not to overlap with r2.
 // Rectangle r1 only needs to be moved by one component of result.
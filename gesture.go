@@ -0,0 +1,257 @@
+package gogame
+
+import "math"
+
+// GestureKind specifies which gesture has been recognized.
+type GestureKind int
+
+// Enumeration of all gesture kinds.
+const (
+	GestureTap GestureKind = iota
+	GestureDoubleTap
+	GestureLongPress
+	GestureSwipe
+	GesturePinch
+	GestureRotate
+)
+
+// Gesture is a single gesture recognized by a GestureRecognizer.
+type Gesture struct {
+	Kind GestureKind
+
+	// Pos is where the gesture happened. For swipes, it's where the swipe began. For pinches
+	// and rotations, it's the center between the two fingers.
+	Pos Vec
+
+	// Delta is the movement of a swipe from its beginning to its end.
+	Delta Vec
+
+	// Scale is how many times the distance between the fingers of a pinch changed since the
+	// previous frame. Spreading the fingers gives a scale greater than 1.
+	Scale float64
+
+	// Angle is how much the fingers of a rotation rotated since the previous frame (in radians).
+	Angle float64
+}
+
+// NewGestureRecognizer creates a gesture recognizer with reasonable default timings and
+// distances and with mouse emulation turned on.
+func NewGestureRecognizer() *GestureRecognizer {
+	return &GestureRecognizer{
+		TapTime:           0.3,
+		TapDistance:       10,
+		DoubleTapTime:     0.3,
+		DoubleTapDistance: 30,
+		LongPressTime:     0.5,
+		SwipeTime:         0.5,
+		SwipeDistance:     50,
+		EmulateMouse:      true,
+	}
+}
+
+// GestureRecognizer recognizes taps, double-taps, long-presses, swipes, pinches and rotations
+// from touch input. Call Update once per frame and then check Gestures.
+// All times are in seconds and all distances are in pixels.
+type GestureRecognizer struct {
+	// TapTime is the longest a finger can touch the screen to make a tap.
+	TapTime float64
+
+	// TapDistance is the farthest a finger can move during a tap or a long-press.
+	TapDistance float64
+
+	// DoubleTapTime is the longest time between two taps of a double-tap.
+	DoubleTapTime float64
+
+	// DoubleTapDistance is the farthest two taps of a double-tap can be from each other.
+	DoubleTapDistance float64
+
+	// LongPressTime is how long a finger needs to stay still to make a long-press.
+	LongPressTime float64
+
+	// SwipeTime is the longest a swipe can take.
+	SwipeTime float64
+
+	// SwipeDistance is the shortest distance a finger needs to move to make a swipe.
+	SwipeDistance float64
+
+	// EmulateMouse makes the left mouse button act as a finger when nothing touches the
+	// screen. Multi-finger gestures can't be emulated.
+	EmulateMouse bool
+
+	time       float64
+	pointers   []pointer
+	mouseDown  bool
+	multi      bool
+	hasLastTap bool
+	lastTapAt  float64
+	lastTapPos Vec
+	gestures   []Gesture
+}
+
+// pointer is a finger (or the emulated mouse) tracked by a gesture recognizer.
+type pointer struct {
+	id          int64
+	start, pos  Vec
+	prevPos     Vec
+	startTime   float64
+	moved       bool
+	longPressed bool
+	ended       bool
+}
+
+// mousePointerID is the ID of the emulated mouse finger. SDL never gives negative finger IDs.
+const mousePointerID = -1
+
+// Gestures returns all gestures recognized during the last call to Update.
+func (g *GestureRecognizer) Gestures() []Gesture {
+	return append([]Gesture(nil), g.gestures...)
+}
+
+// Update recognizes gestures from the current state of the input. Dt is the time that passed
+// since the previous call to Update.
+func (g *GestureRecognizer) Update(in Input, dt float64) {
+	g.time += dt
+	g.gestures = g.gestures[:0]
+
+	touches := in.Touches()
+	if g.EmulateMouse {
+		// a click can be pressed and released within a single frame, so count the presses
+		// instead of checking the state of the button
+		pressed := in.MousePresses(MouseButtonLeft) > 0
+		began := false
+		// touch screens emulate mouse clicks too, so only use the mouse if there's no finger
+		if pressed && !g.mouseDown && len(touches) == 0 && len(g.pointers) == 0 {
+			g.mouseDown, began = true, true
+		}
+		if g.mouseDown {
+			up := !in.MouseDown(MouseButtonLeft)
+			touches = append(touches, Touch{
+				ID:        mousePointerID,
+				Pos:       in.MousePosition(),
+				Pressure:  1,
+				JustBegan: began,
+				JustEnded: up,
+			})
+			g.mouseDown = !up
+		}
+	}
+
+	// pointers which are gone without a trace are treated as lifted
+	for i := range g.pointers {
+		g.pointers[i].ended = true
+	}
+	for _, touch := range touches {
+		g.track(touch)
+	}
+	if len(g.pointers) >= 2 {
+		g.multi = true
+	}
+
+	if g.multi {
+		g.recognizeMulti()
+	} else {
+		for i := range g.pointers {
+			g.recognizeSingle(&g.pointers[i])
+		}
+	}
+
+	pointers := g.pointers[:0]
+	for _, p := range g.pointers {
+		if !p.ended {
+			pointers = append(pointers, p)
+		}
+	}
+	g.pointers = pointers
+	if len(g.pointers) == 0 {
+		g.multi = false
+	}
+}
+
+func (g *GestureRecognizer) track(touch Touch) {
+	for i := range g.pointers {
+		p := &g.pointers[i]
+		if p.id == touch.ID {
+			p.prevPos, p.pos = p.pos, touch.Pos
+			if p.pos.S(p.start).Len() > g.TapDistance {
+				p.moved = true
+			}
+			p.ended = touch.JustEnded
+			return
+		}
+	}
+	g.pointers = append(g.pointers, pointer{
+		id:        touch.ID,
+		start:     touch.Pos,
+		pos:       touch.Pos,
+		prevPos:   touch.Pos,
+		startTime: g.time,
+		ended:     touch.JustEnded,
+	})
+}
+
+func (g *GestureRecognizer) recognizeSingle(p *pointer) {
+	duration := g.time - p.startTime
+
+	if !p.moved && !p.longPressed && !p.ended && duration >= g.LongPressTime {
+		p.longPressed = true
+		g.gestures = append(g.gestures, Gesture{Kind: GestureLongPress, Pos: p.pos})
+	}
+
+	if !p.ended || p.longPressed {
+		return
+	}
+
+	if !p.moved && duration <= g.TapTime {
+		g.gestures = append(g.gestures, Gesture{Kind: GestureTap, Pos: p.pos})
+		if g.hasLastTap &&
+			g.time-g.lastTapAt <= g.DoubleTapTime &&
+			p.pos.S(g.lastTapPos).Len() <= g.DoubleTapDistance {
+			g.gestures = append(g.gestures, Gesture{Kind: GestureDoubleTap, Pos: p.pos})
+			g.hasLastTap = false // three taps don't make two double-taps
+		} else {
+			g.hasLastTap = true
+			g.lastTapAt = g.time
+			g.lastTapPos = p.pos
+		}
+		return
+	}
+
+	delta := p.pos.S(p.start)
+	if duration <= g.SwipeTime && delta.Len() >= g.SwipeDistance {
+		g.gestures = append(g.gestures, Gesture{Kind: GestureSwipe, Pos: p.start, Delta: delta})
+	}
+}
+
+func (g *GestureRecognizer) recognizeMulti() {
+	if len(g.pointers) < 2 {
+		return
+	}
+	p, q := g.pointers[0], g.pointers[1]
+
+	prev := q.prevPos.S(p.prevPos)
+	curr := q.pos.S(p.pos)
+	center := p.pos.A(q.pos).D(2)
+
+	if prev.Len() > 0 && curr.Len() != prev.Len() {
+		g.gestures = append(g.gestures, Gesture{
+			Kind:  GesturePinch,
+			Pos:   center,
+			Scale: curr.Len() / prev.Len(),
+		})
+	}
+
+	angle := math.Atan2(curr.Y, curr.X) - math.Atan2(prev.Y, prev.X)
+	if angle > math.Pi {
+		angle -= 2 * math.Pi
+	}
+	if angle <= -math.Pi {
+		angle += 2 * math.Pi
+	}
+	if prev.Len() > 0 && angle != 0 {
+		g.gestures = append(g.gestures, Gesture{
+			Kind:  GestureRotate,
+			Pos:   center,
+			Angle: angle,
+		})
+	}
+}
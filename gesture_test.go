@@ -0,0 +1,179 @@
+package gogame
+
+import (
+	"math"
+	"testing"
+)
+
+func (in *fakeInput) MousePosition() Vec { return in.mousePos }
+func (in *fakeInput) Touches() []Touch   { return in.touches }
+
+// finger creates a touch of a finger for one frame of a gesture test.
+func finger(id int64, x, y float64, began, ended bool) Touch {
+	return Touch{ID: id, Pos: Vec{X: x, Y: y}, Pressure: 1, JustBegan: began, JustEnded: ended}
+}
+
+func TestGestureRecognizer(t *testing.T) {
+	type frame struct {
+		dt      float64
+		touches []Touch
+		want    []GestureKind
+	}
+	tests := []struct {
+		name   string
+		frames []frame
+	}{
+		{"tap", []frame{
+			{0.1, []Touch{finger(1, 10, 10, true, false)}, nil},
+			{0.1, []Touch{finger(1, 12, 10, false, true)}, []GestureKind{GestureTap}},
+		}},
+		{"double tap", []frame{
+			{0.1, []Touch{finger(1, 10, 10, true, false)}, nil},
+			{0.1, []Touch{finger(1, 10, 10, false, true)}, []GestureKind{GestureTap}},
+			{0.1, []Touch{finger(2, 20, 10, true, false)}, nil},
+			{0.1, []Touch{finger(2, 20, 10, false, true)},
+				[]GestureKind{GestureTap, GestureDoubleTap}},
+		}},
+		{"taps too far apart", []frame{
+			{0.1, []Touch{finger(1, 10, 10, true, false)}, nil},
+			{0.1, []Touch{finger(1, 10, 10, false, true)}, []GestureKind{GestureTap}},
+			{0.1, []Touch{finger(2, 100, 10, true, false)}, nil},
+			{0.1, []Touch{finger(2, 100, 10, false, true)}, []GestureKind{GestureTap}},
+		}},
+		{"long press", []frame{
+			{0.1, []Touch{finger(1, 10, 10, true, false)}, nil},
+			{0.3, []Touch{finger(1, 10, 10, false, false)}, nil},
+			{0.3, []Touch{finger(1, 10, 10, false, false)}, []GestureKind{GestureLongPress}},
+			{0.3, []Touch{finger(1, 10, 10, false, false)}, nil},
+			{0.1, []Touch{finger(1, 10, 10, false, true)}, nil},
+		}},
+		{"swipe", []frame{
+			{0.1, []Touch{finger(1, 10, 10, true, false)}, nil},
+			{0.1, []Touch{finger(1, 60, 10, false, false)}, nil},
+			{0.1, []Touch{finger(1, 110, 10, false, true)}, []GestureKind{GestureSwipe}},
+		}},
+		{"slow drag is no swipe", []frame{
+			{0.1, []Touch{finger(1, 10, 10, true, false)}, nil},
+			{0.4, []Touch{finger(1, 60, 10, false, false)}, nil},
+			{0.4, []Touch{finger(1, 110, 10, false, true)}, nil},
+		}},
+		{"pinch", []frame{
+			{0.1, []Touch{finger(1, 0, 0, true, false), finger(2, 100, 0, true, false)}, nil},
+			{0.1, []Touch{finger(1, -50, 0, false, false), finger(2, 150, 0, false, false)},
+				[]GestureKind{GesturePinch}},
+			{0.1, []Touch{finger(1, -50, 0, false, true), finger(2, 150, 0, false, true)}, nil},
+		}},
+		{"rotate", []frame{
+			{0.1, []Touch{finger(1, 0, 0, true, false), finger(2, 100, 0, true, false)}, nil},
+			{0.1, []Touch{finger(1, 50, -50, false, false), finger(2, 50, 50, false, false)},
+				[]GestureKind{GestureRotate}},
+		}},
+	}
+
+	for _, test := range tests {
+		in := newFakeInput()
+		g := NewGestureRecognizer()
+		for i, f := range test.frames {
+			in.frame()
+			in.touches = f.touches
+			g.Update(in, f.dt)
+
+			var got []GestureKind
+			for _, gesture := range g.Gestures() {
+				got = append(got, gesture.Kind)
+			}
+			if len(got) != len(f.want) {
+				t.Errorf("%s, frame %d: got %v, want %v", test.name, i, got, f.want)
+				continue
+			}
+			for j := range got {
+				if got[j] != f.want[j] {
+					t.Errorf("%s, frame %d: got %v, want %v", test.name, i, got, f.want)
+					break
+				}
+			}
+		}
+	}
+}
+
+func TestGestureValues(t *testing.T) {
+	in := newFakeInput()
+	g := NewGestureRecognizer()
+
+	in.touches = []Touch{finger(1, 0, 0, true, false), finger(2, 100, 0, true, false)}
+	g.Update(in, 0.1)
+	in.touches = []Touch{finger(1, 50, -100, false, false), finger(2, 50, 100, false, false)}
+	g.Update(in, 0.1)
+
+	for _, gesture := range g.Gestures() {
+		switch gesture.Kind {
+		case GesturePinch:
+			if math.Abs(gesture.Scale-2) > 1e-9 {
+				t.Errorf("pinch: got scale %v, want 2", gesture.Scale)
+			}
+			if gesture.Pos != (Vec{X: 50, Y: 0}) {
+				t.Errorf("pinch: got pos %v, want the center between the fingers", gesture.Pos)
+			}
+		case GestureRotate:
+			if math.Abs(gesture.Angle-math.Pi/2) > 1e-9 {
+				t.Errorf("rotate: got angle %v, want %v", gesture.Angle, math.Pi/2)
+			}
+		}
+	}
+
+	in = newFakeInput()
+	g = NewGestureRecognizer()
+	in.touches = []Touch{finger(1, 10, 20, true, false)}
+	g.Update(in, 0.1)
+	in.touches = []Touch{finger(1, 70, 100, false, true)}
+	g.Update(in, 0.1)
+	gestures := g.Gestures()
+	if len(gestures) != 1 || gestures[0].Delta != (Vec{X: 60, Y: 80}) ||
+		gestures[0].Pos != (Vec{X: 10, Y: 20}) {
+		t.Errorf("swipe: got %v, want a swipe from (10, 20) by (60, 80)", gestures)
+	}
+}
+
+func TestGestureMouseEmulation(t *testing.T) {
+	in := newFakeInput()
+	g := NewGestureRecognizer()
+
+	in.frame()
+	in.mouse[MouseButtonLeft] = true
+	in.mousePresses[MouseButtonLeft] = 1
+	in.mousePos = Vec{X: 5, Y: 5}
+	g.Update(in, 0.1)
+
+	in.frame()
+	in.mouse[MouseButtonLeft] = false
+	in.mouseReleases[MouseButtonLeft] = 1
+	g.Update(in, 0.1)
+
+	gestures := g.Gestures()
+	if len(gestures) != 1 || gestures[0].Kind != GestureTap || gestures[0].Pos != in.mousePos {
+		t.Errorf("got %v, want a tap at %v", gestures, in.mousePos)
+	}
+
+	// pressed and released within one frame
+	in.frame()
+	in.mousePresses[MouseButtonLeft] = 1
+	in.mouseReleases[MouseButtonLeft] = 1
+	in.mousePos = Vec{X: 50, Y: 5}
+	g.Update(in, 0.1)
+	gestures = g.Gestures()
+	if len(gestures) != 1 || gestures[0].Kind != GestureTap || gestures[0].Pos != in.mousePos {
+		t.Errorf("click within a frame: got %v, want a tap at %v", gestures, in.mousePos)
+	}
+
+	g.EmulateMouse = false
+	in.frame()
+	in.mouse[MouseButtonLeft] = true
+	in.mousePresses[MouseButtonLeft] = 1
+	g.Update(in, 0.1)
+	in.frame()
+	in.mouse[MouseButtonLeft] = false
+	g.Update(in, 0.1)
+	if gestures := g.Gestures(); len(gestures) != 0 {
+		t.Errorf("without emulation: got %v, want none", gestures)
+	}
+}
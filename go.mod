@@ -0,0 +1,8 @@
+module github.com/faiface/gogame
+
+go 1.18
+
+require (
+	github.com/pkg/errors v0.9.1
+	github.com/veandco/go-sdl2 v0.4.39
+)
@@ -0,0 +1,222 @@
+package gogame
+
+import "math"
+
+// GradientKind specifies the shape of a gradient.
+type GradientKind int
+
+// Enumeration of all gradient kinds.
+const (
+	GradientLinear GradientKind = iota
+	GradientRadial
+)
+
+// ColorStop is a color at an offset along a gradient.
+type ColorStop struct {
+	Offset float64
+	Color  Color
+}
+
+// Gradient is a fill with colors changing smoothly across space.
+type Gradient struct {
+	Kind GradientKind
+
+	// In a linear gradient, the colors change along the line from Start (offset 0) to End
+	// (offset 1) and stay the same across it. In a radial gradient, Start is the center
+	// (offset 0) and End is any point on the circle of offset 1.
+	Start, End Vec
+
+	// Stops are the colors at offsets, sorted by the offset. Before the first stop and after
+	// the last stop, their colors continue.
+	Stops []ColorStop
+}
+
+// LinearGradient creates a linear gradient going from one color at start to another color
+// at end.
+func LinearGradient(start, end Vec, from, to Color) Gradient {
+	return Gradient{
+		Kind:  GradientLinear,
+		Start: start,
+		End:   end,
+		Stops: []ColorStop{{0, from}, {1, to}},
+	}
+}
+
+// RadialGradient creates a radial gradient going from one color in the center to another
+// color at the radius.
+func RadialGradient(center Vec, radius float64, inner, outer Color) Gradient {
+	return Gradient{
+		Kind:  GradientRadial,
+		Start: center,
+		End:   center.A(Vec{X: radius, Y: 0}),
+		Stops: []ColorStop{{0, inner}, {1, outer}},
+	}
+}
+
+// At returns the color of a gradient at a point.
+func (g Gradient) At(point Vec) Color {
+	if len(g.Stops) == 0 {
+		return Color{}
+	}
+	offset := g.offset(point)
+	if offset <= g.Stops[0].Offset {
+		return g.Stops[0].Color
+	}
+	for i := 1; i < len(g.Stops); i++ {
+		a, b := g.Stops[i-1], g.Stops[i]
+		if offset <= b.Offset {
+			if b.Offset == a.Offset {
+				return b.Color // a sharp edge between the stops
+			}
+			return lerpColor(a.Color, b.Color, (offset-a.Offset)/(b.Offset-a.Offset))
+		}
+	}
+	return g.Stops[len(g.Stops)-1].Color
+}
+
+func (g Gradient) offset(point Vec) float64 {
+	axis := g.End.S(g.Start)
+	if axis.Len2() == 0 {
+		return 0
+	}
+	if g.Kind == GradientRadial {
+		return point.S(g.Start).Len() / axis.Len()
+	}
+	return (point.S(g.Start).X*axis.X + point.S(g.Start).Y*axis.Y) / axis.Len2()
+}
+
+func lerpColor(c, d Color, t float64) Color {
+	return Color{
+		R: c.R + (d.R-c.R)*t,
+		G: c.G + (d.G-c.G)*t,
+		B: c.B + (d.B-c.B)*t,
+		A: c.A + (d.A-c.A)*t,
+	}
+}
+
+// gradientMesh creates a mesh filling a polygon with a gradient. Colors are interpolated
+// linearly across the triangles of a mesh, so linear gradients are cut into bands between
+// the stops, and radial gradients into triangles small enough not to differ visibly.
+func gradientMesh(points []Vec, g Gradient) *Mesh {
+	mesh := &Mesh{}
+	indices := triangulate(points)
+	for i := 0; i+2 < len(indices); i += 3 {
+		triangle := []Vec{points[indices[i]], points[indices[i+1]], points[indices[i+2]]}
+		if g.Kind == GradientRadial {
+			g.addSubdivided(mesh, triangle, 6)
+		} else {
+			g.addBands(mesh, triangle)
+		}
+	}
+	return mesh
+}
+
+// addBands adds a triangle cut into parts where the gradient changes linearly.
+func (g Gradient) addBands(mesh *Mesh, triangle []Vec) {
+	bounds := []float64{math.Inf(-1)}
+	for _, stop := range g.Stops {
+		bounds = append(bounds, stop.Offset)
+	}
+	bounds = append(bounds, math.Inf(1))
+
+	for i := 1; i < len(bounds); i++ {
+		if bounds[i-1] == bounds[i] {
+			continue // a sharp edge between two stops
+		}
+		band := clipByOffset(triangle, g.offset, bounds[i-1], 1)
+		band = clipByOffset(band, g.offset, bounds[i], -1)
+		for j := 2; j < len(band); j++ {
+			for _, p := range []Vec{band[0], band[j-1], band[j]} {
+				mesh.Vertices = append(mesh.Vertices, Vertex{Pos: p, Color: g.bandColor(i, p)})
+			}
+		}
+	}
+}
+
+// bandColor returns the color at a point of the band before the stop i-1, or after the last
+// stop. Unlike At, it picks the right color on a sharp edge between two stops.
+func (g Gradient) bandColor(i int, p Vec) Color {
+	switch {
+	case len(g.Stops) == 0:
+		return Color{}
+	case i == 1:
+		return g.Stops[0].Color
+	case i > len(g.Stops):
+		return g.Stops[len(g.Stops)-1].Color
+	}
+	a, b := g.Stops[i-2], g.Stops[i-1]
+	t := clamp((g.offset(p)-a.Offset)/(b.Offset-a.Offset), 0, 1)
+	return lerpColor(a.Color, b.Color, t)
+}
+
+// addSubdivided adds a triangle, recursively split into four until interpolating the colors
+// of its corners matches the gradient, or until depth runs out.
+func (g Gradient) addSubdivided(mesh *Mesh, triangle []Vec, depth int) {
+	a, b, c := triangle[0], triangle[1], triangle[2]
+	if depth == 0 || g.linearWithin(a, b, c) {
+		for _, p := range triangle {
+			mesh.Vertices = append(mesh.Vertices, Vertex{Pos: p, Color: g.At(p)})
+		}
+		return
+	}
+	ab, bc, ca := a.A(b).D(2), b.A(c).D(2), c.A(a).D(2)
+	g.addSubdivided(mesh, []Vec{a, ab, ca}, depth-1)
+	g.addSubdivided(mesh, []Vec{ab, b, bc}, depth-1)
+	g.addSubdivided(mesh, []Vec{ca, bc, c}, depth-1)
+	g.addSubdivided(mesh, []Vec{ab, bc, ca}, depth-1)
+}
+
+// linearWithin checks if the gradient at the midpoints of the edges of a triangle and at its
+// center matches the interpolated colors of the corners. Triangles with edges longer than the
+// radius are never considered linear, because the whole gradient could hide within them.
+func (g Gradient) linearWithin(a, b, c Vec) bool {
+	size := g.End.S(g.Start).Len()
+	if size == 0 {
+		return true
+	}
+	if b.S(a).Len() > size || c.S(b).Len() > size || a.S(c).Len() > size {
+		return false
+	}
+
+	const tolerance = 1.0 / 255
+	ca, cb, cc := g.At(a), g.At(b), g.At(c)
+	checks := []struct {
+		point Vec
+		color Color
+	}{
+		{a.A(b).D(2), lerpColor(ca, cb, 0.5)},
+		{b.A(c).D(2), lerpColor(cb, cc, 0.5)},
+		{c.A(a).D(2), lerpColor(cc, ca, 0.5)},
+		{a.A(b).A(c).D(3), lerpColor(lerpColor(ca, cb, 0.5), cc, 1.0/3)},
+	}
+	for _, check := range checks {
+		d := g.At(check.point)
+		if math.Abs(d.R-check.color.R) > tolerance ||
+			math.Abs(d.G-check.color.G) > tolerance ||
+			math.Abs(d.B-check.color.B) > tolerance ||
+			math.Abs(d.A-check.color.A) > tolerance {
+			return false
+		}
+	}
+	return true
+}
+
+// clipByOffset clips a convex polygon to the part where offset(p)*sign >= bound*sign.
+func clipByOffset(points []Vec, offset func(Vec) float64, bound, sign float64) []Vec {
+	if math.IsInf(bound, 0) {
+		return points
+	}
+	var clipped []Vec
+	for i := range points {
+		a, b := points[i], points[(i+1)%len(points)]
+		da, db := (offset(a)-bound)*sign, (offset(b)-bound)*sign
+		if da >= 0 {
+			clipped = append(clipped, a)
+		}
+		if (da >= 0) != (db >= 0) {
+			t := da / (da - db)
+			clipped = append(clipped, a.A(b.S(a).M(t)))
+		}
+	}
+	return clipped
+}
@@ -0,0 +1,114 @@
+package gogame
+
+import (
+	"math"
+	"testing"
+)
+
+func colorsClose(c, d Color) bool {
+	const tolerance = 1e-9
+	return math.Abs(c.R-d.R) < tolerance && math.Abs(c.G-d.G) < tolerance &&
+		math.Abs(c.B-d.B) < tolerance && math.Abs(c.A-d.A) < tolerance
+}
+
+func TestGradientAt(t *testing.T) {
+	black, white := Colors["black"], Colors["white"]
+	red, blue := Colors["red"], Colors["blue"]
+	gray := Color{R: 0.5, G: 0.5, B: 0.5, A: 1}
+
+	linear := LinearGradient(Vec{X: 0, Y: 0}, Vec{X: 10, Y: 0}, black, white)
+	radial := RadialGradient(Vec{X: 0, Y: 0}, 10, black, white)
+	sharp := Gradient{
+		Kind:  GradientLinear,
+		Start: Vec{X: 0, Y: 0},
+		End:   Vec{X: 10, Y: 0},
+		Stops: []ColorStop{{0, black}, {0.5, red}, {0.5, blue}, {1, white}},
+	}
+	sharpStart := Gradient{
+		Kind:  GradientLinear,
+		Start: Vec{X: 0, Y: 0},
+		End:   Vec{X: 10, Y: 0},
+		Stops: []ColorStop{{0, red}, {0, blue}},
+	}
+
+	tests := []struct {
+		name  string
+		g     Gradient
+		point Vec
+		want  Color
+	}{
+		{"linear start", linear, Vec{X: 0, Y: 0}, black},
+		{"linear middle", linear, Vec{X: 5, Y: 7}, gray},
+		{"linear end", linear, Vec{X: 10, Y: -3}, white},
+		{"linear before start", linear, Vec{X: -5, Y: 0}, black},
+		{"linear after end", linear, Vec{X: 15, Y: 0}, white},
+		{"radial center", radial, Vec{X: 0, Y: 0}, black},
+		{"radial middle", radial, Vec{X: 3, Y: 4}, gray},
+		{"radial outside", radial, Vec{X: 0, Y: -20}, white},
+		{"sharp before the edge", sharp, Vec{X: 2.5, Y: 0}, Color{R: 0.5, A: 1}},
+		{"sharp on the edge", sharp, Vec{X: 5, Y: 0}, red},
+		{"sharp after the edge", sharp, Vec{X: 7.5, Y: 0}, Color{R: 0.5, G: 0.5, B: 1, A: 1}},
+		{"sharp first stops", sharpStart, Vec{X: 0, Y: 0}, red},
+		{"sharp first stops after", sharpStart, Vec{X: 5, Y: 0}, blue},
+		{"no stops", Gradient{}, Vec{X: 1, Y: 1}, Color{}},
+		{"no axis", Gradient{Stops: []ColorStop{{0, red}, {1, blue}}}, Vec{X: 1, Y: 1}, red},
+	}
+	for _, test := range tests {
+		got := test.g.At(test.point)
+		if !colorsClose(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+		if math.IsNaN(got.R) || math.IsNaN(got.A) {
+			t.Errorf("%s: got NaN", test.name)
+		}
+	}
+}
+
+func TestGradientMesh(t *testing.T) {
+	square := []Vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}, {X: 0, Y: 10}}
+	tests := []struct {
+		name   string
+		g      Gradient
+		linear bool // the colors change linearly within the triangles
+	}{
+		{"linear", LinearGradient(Vec{X: 0, Y: 0}, Vec{X: 10, Y: 10}, Colors["red"], Colors["blue"]),
+			true},
+		{"sharp", Gradient{
+			Kind:  GradientLinear,
+			Start: Vec{X: 0, Y: 0},
+			End:   Vec{X: 10, Y: 0},
+			Stops: []ColorStop{{0.3, Colors["red"]}, {0.3, Colors["blue"]}, {0.6, Colors["green"]}},
+		}, true},
+		{"radial", RadialGradient(Vec{X: 5, Y: 5}, 5, Colors["white"], Colors["black"]), false},
+	}
+	for _, test := range tests {
+		mesh := gradientMesh(square, test.g)
+		if mesh.Indices != nil || len(mesh.Vertices)%3 != 0 {
+			t.Errorf("%s: got %d vertices and indices %v, want whole triangles", test.name,
+				len(mesh.Vertices), mesh.Indices)
+			continue
+		}
+
+		// the triangles cover the square exactly and have the colors of the gradient
+		area := 0.0
+		for i := 0; i < len(mesh.Vertices); i += 3 {
+			a, b, c := mesh.Vertices[i], mesh.Vertices[i+1], mesh.Vertices[i+2]
+			area += math.Abs(polygonArea([]Vec{a.Pos, b.Pos, c.Pos}))
+
+			center := a.Pos.A(b.Pos).A(c.Pos).D(3)
+			got := lerpColor(lerpColor(a.Color, b.Color, 0.5), c.Color, 1.0/3)
+			want := test.g.At(center)
+			if !test.linear {
+				// corners only, the colors are interpolated just closely enough
+				center, got, want = a.Pos, a.Color, test.g.At(a.Pos)
+			}
+			if !colorsClose(got, want) {
+				t.Errorf("%s: got %v at %v, want %v", test.name, got, center, want)
+				break
+			}
+		}
+		if math.Abs(area-100) > 1e-6 {
+			t.Errorf("%s: got triangles with area %v, want 100", test.name, area)
+		}
+	}
+}
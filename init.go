@@ -4,6 +4,7 @@ import (
 	"errors"
 
 	"github.com/veandco/go-sdl2/sdl"
+	"github.com/veandco/go-sdl2/ttf"
 )
 
 // Init initializes Gogame (and SDL2). Call this before using Gogame.
@@ -12,10 +13,19 @@ func Init() error {
 	if err != nil {
 		return errors.New("failed to initialize SDL2")
 	}
+	err = ttf.Init()
+	if err != nil {
+		return errors.New("failed to initialize SDL2_ttf")
+	}
 	return nil
 }
 
-// Quit deinitializes Gogame. Call this when you are done with Gogame.
+// Quit deinitializes Gogame. Call this when you are done with Gogame. It closes all fonts
+// loaded with LoadFont.
 func Quit() {
+	for _, font := range trueTypeFonts {
+		font.Close()
+	}
+	ttf.Quit()
 	sdl.Quit()
 }
@@ -7,6 +7,10 @@ type Input interface {
 	WindowInput
 	MouseInput
 	KeyboardInput
+	TouchInput
+	EventInput
+	ClipboardInput
+	DropInput
 }
 
 // WindowInput gets input from a window.
@@ -42,8 +46,14 @@ type MouseInput interface {
 	MousePosition() Vec
 
 	// MouseDelta returns the difference between mouse's current and previous position.
+	// In relative mouse mode, the position does not change and MouseDelta returns the
+	// raw movement of the mouse instead.
 	MouseDelta() Vec
 
+	// MouseWheel returns how much the mouse wheel has scrolled since the previous frame.
+	// Positive Y means scrolling away from the user, positive X means scrolling to the right.
+	MouseWheel() Vec
+
 	// MouseDown checks if a mouse button is currently pressed down.
 	MouseDown(button int) bool
 
@@ -52,6 +62,15 @@ type MouseInput interface {
 
 	// MouseJustUp checks if a mouse button has just been released up.
 	MouseJustUp(button int) bool
+
+	// MousePresses returns how many times a mouse button has been pressed down since the
+	// previous frame. Unlike MouseJustDown, this counts presses that were released within
+	// the same frame too.
+	MousePresses(button int) int
+
+	// MouseReleases returns how many times a mouse button has been released up since the
+	// previous frame.
+	MouseReleases(button int) int
 }
 
 // KeyboardInput gets input from a keyboard device.
@@ -64,6 +83,112 @@ type KeyboardInput interface {
 
 	// KeyJustUp checks if a key has just been released up.
 	KeyJustUp(key int) bool
+
+	// KeyPresses returns how many times a key has been pressed down since the previous frame.
+	// Unlike KeyJustDown, this counts presses that were released within the same frame too.
+	// Repeated presses generated by holding a key down are not counted.
+	KeyPresses(key int) int
+
+	// KeyReleases returns how many times a key has been released up since the previous frame.
+	KeyReleases(key int) int
+
+	// ScancodeDown checks if a physical key is currently pressed down. Unlike keys, scancodes
+	// don't depend on the keyboard layout, e.g. ScancodeW is the same key on QWERTY and
+	// AZERTY keyboards (labeled Z on the latter).
+	ScancodeDown(scancode int) bool
+
+	// ScancodeJustDown checks if a physical key has just been pressed down.
+	ScancodeJustDown(scancode int) bool
+
+	// ScancodeJustUp checks if a physical key has just been released up.
+	ScancodeJustUp(scancode int) bool
+
+	// KeyMods returns the currently active modifiers (a combination of Mod* flags).
+	KeyMods() int
+
+	// ModDown checks if any of the modifiers in mod is active, e.g. ModDown(ModCtrl) checks
+	// for either of the Ctrl keys.
+	ModDown(mod int) bool
+
+	// KeyFromScancode returns the key at the position of a scancode in the current keyboard
+	// layout.
+	KeyFromScancode(scancode int) int
+
+	// ScancodeFromKey returns the position of a key in the current keyboard layout.
+	ScancodeFromKey(key int) int
+
+	// KeyName returns a human-readable name of a key, such as "Left Shift". If the key has no
+	// name, an empty string is returned.
+	KeyName(key int) string
+
+	// ScancodeName returns a human-readable name of a scancode. If the scancode has no name,
+	// an empty string is returned.
+	ScancodeName(scancode int) string
+}
+
+// TouchInput gets input from a touch screen.
+type TouchInput interface {
+	// Touches returns all fingers currently touching the screen in the order they touched it.
+	// Fingers that have just been lifted are included too (with JustEnded set), so that their
+	// last position is not lost.
+	Touches() []Touch
+}
+
+// EventInput gets input as a stream of events.
+type EventInput interface {
+	// Events returns all input events that happened since the previous frame, in the order
+	// they happened.
+	Events() []Event
+}
+
+// ClipboardInput reads and writes the system clipboard.
+type ClipboardInput interface {
+	// ClipboardText returns the text in the clipboard. If the clipboard is empty or doesn't
+	// contain text, an empty string is returned.
+	ClipboardText() string
+
+	// ClipboardSetText puts text into the clipboard.
+	ClipboardSetText(text string)
+}
+
+// DropInput gets files and text dragged and dropped onto the window.
+type DropInput interface {
+	// Drops returns all files and text dropped onto the window since the previous frame,
+	// in the order they were dropped.
+	Drops() []Drop
+}
+
+// Drop is a single file or text dropped onto the window.
+type Drop struct {
+	// Path is the path of a dropped file. It's empty if text was dropped.
+	Path string
+
+	// Text is the dropped text. It's empty if a file was dropped.
+	Text string
+
+	// Pos is where the drop happened relative to the window.
+	Pos Vec
+}
+
+// Touch is a single finger touching a touch screen.
+type Touch struct {
+	// ID identifies the finger for as long as it touches the screen.
+	ID int64
+
+	// Pos is the position of the finger relative to the window.
+	Pos Vec
+
+	// Delta is the difference between the finger's current and previous position.
+	Delta Vec
+
+	// Pressure is the pressure of the finger, between 0 and 1.
+	Pressure float64
+
+	// JustBegan is true if the finger has just touched the screen.
+	JustBegan bool
+
+	// JustEnded is true if the finger has just been lifted from the screen.
+	JustEnded bool
 }
 
 // Enumeration of all mouse buttons.
@@ -75,6 +200,28 @@ const (
 	MouseButtonX2     = sdl.BUTTON_X2
 )
 
+// Enumeration of all key modifiers. ModCtrl, ModShift, ModAlt and ModGui combine their left and
+// right variants.
+const (
+	ModNone   = sdl.KMOD_NONE
+	ModLShift = sdl.KMOD_LSHIFT
+	ModRShift = sdl.KMOD_RSHIFT
+	ModLCtrl  = sdl.KMOD_LCTRL
+	ModRCtrl  = sdl.KMOD_RCTRL
+	ModLAlt   = sdl.KMOD_LALT
+	ModRAlt   = sdl.KMOD_RALT
+	ModLGui   = sdl.KMOD_LGUI
+	ModRGui   = sdl.KMOD_RGUI
+	ModNum    = sdl.KMOD_NUM
+	ModCaps   = sdl.KMOD_CAPS
+	ModMode   = sdl.KMOD_MODE
+
+	ModCtrl  = sdl.KMOD_CTRL
+	ModShift = sdl.KMOD_SHIFT
+	ModAlt   = sdl.KMOD_ALT
+	ModGui   = sdl.KMOD_GUI
+)
+
 // Enumeration of all keyboard keys.
 const (
 	KeyUnknown = sdl.K_UNKNOWN
@@ -327,3 +474,156 @@ const (
 	KeyEject          = sdl.K_EJECT
 	KeySleep          = sdl.K_SLEEP
 )
+
+// Enumeration of all scancodes (physical positions of keys on a keyboard). They are named after
+// the keys at their positions on a US QWERTY keyboard.
+const (
+	ScancodeUnknown = sdl.SCANCODE_UNKNOWN
+
+	ScancodeReturn       = sdl.SCANCODE_RETURN
+	ScancodeEscape       = sdl.SCANCODE_ESCAPE
+	ScancodeBackspace    = sdl.SCANCODE_BACKSPACE
+	ScancodeTab          = sdl.SCANCODE_TAB
+	ScancodeSpace        = sdl.SCANCODE_SPACE
+	ScancodeMinus        = sdl.SCANCODE_MINUS
+	ScancodeEquals       = sdl.SCANCODE_EQUALS
+	ScancodeLeftBracket  = sdl.SCANCODE_LEFTBRACKET
+	ScancodeRightBracket = sdl.SCANCODE_RIGHTBRACKET
+	ScancodeBackslash    = sdl.SCANCODE_BACKSLASH
+	ScancodeNonUSHash    = sdl.SCANCODE_NONUSHASH
+	ScancodeSemicolon    = sdl.SCANCODE_SEMICOLON
+	ScancodeApostrophe   = sdl.SCANCODE_APOSTROPHE
+	ScancodeGrave        = sdl.SCANCODE_GRAVE
+	ScancodeComma        = sdl.SCANCODE_COMMA
+	ScancodePeriod       = sdl.SCANCODE_PERIOD
+	ScancodeSlash        = sdl.SCANCODE_SLASH
+
+	Scancode1 = sdl.SCANCODE_1
+	Scancode2 = sdl.SCANCODE_2
+	Scancode3 = sdl.SCANCODE_3
+	Scancode4 = sdl.SCANCODE_4
+	Scancode5 = sdl.SCANCODE_5
+	Scancode6 = sdl.SCANCODE_6
+	Scancode7 = sdl.SCANCODE_7
+	Scancode8 = sdl.SCANCODE_8
+	Scancode9 = sdl.SCANCODE_9
+	Scancode0 = sdl.SCANCODE_0
+
+	ScancodeA = sdl.SCANCODE_A
+	ScancodeB = sdl.SCANCODE_B
+	ScancodeC = sdl.SCANCODE_C
+	ScancodeD = sdl.SCANCODE_D
+	ScancodeE = sdl.SCANCODE_E
+	ScancodeF = sdl.SCANCODE_F
+	ScancodeG = sdl.SCANCODE_G
+	ScancodeH = sdl.SCANCODE_H
+	ScancodeI = sdl.SCANCODE_I
+	ScancodeJ = sdl.SCANCODE_J
+	ScancodeK = sdl.SCANCODE_K
+	ScancodeL = sdl.SCANCODE_L
+	ScancodeM = sdl.SCANCODE_M
+	ScancodeN = sdl.SCANCODE_N
+	ScancodeO = sdl.SCANCODE_O
+	ScancodeP = sdl.SCANCODE_P
+	ScancodeQ = sdl.SCANCODE_Q
+	ScancodeR = sdl.SCANCODE_R
+	ScancodeS = sdl.SCANCODE_S
+	ScancodeT = sdl.SCANCODE_T
+	ScancodeU = sdl.SCANCODE_U
+	ScancodeV = sdl.SCANCODE_V
+	ScancodeW = sdl.SCANCODE_W
+	ScancodeX = sdl.SCANCODE_X
+	ScancodeY = sdl.SCANCODE_Y
+	ScancodeZ = sdl.SCANCODE_Z
+
+	ScancodeCapsLock = sdl.SCANCODE_CAPSLOCK
+
+	ScancodeF1  = sdl.SCANCODE_F1
+	ScancodeF2  = sdl.SCANCODE_F2
+	ScancodeF3  = sdl.SCANCODE_F3
+	ScancodeF4  = sdl.SCANCODE_F4
+	ScancodeF5  = sdl.SCANCODE_F5
+	ScancodeF6  = sdl.SCANCODE_F6
+	ScancodeF7  = sdl.SCANCODE_F7
+	ScancodeF8  = sdl.SCANCODE_F8
+	ScancodeF9  = sdl.SCANCODE_F9
+	ScancodeF10 = sdl.SCANCODE_F10
+	ScancodeF11 = sdl.SCANCODE_F11
+	ScancodeF12 = sdl.SCANCODE_F12
+
+	ScancodePrintScreen = sdl.SCANCODE_PRINTSCREEN
+	ScancodeScrollLock  = sdl.SCANCODE_SCROLLLOCK
+	ScancodePause       = sdl.SCANCODE_PAUSE
+	ScancodeInsert      = sdl.SCANCODE_INSERT
+	ScancodeHome        = sdl.SCANCODE_HOME
+	ScancodePageUp      = sdl.SCANCODE_PAGEUP
+	ScancodeDelete      = sdl.SCANCODE_DELETE
+	ScancodeEnd         = sdl.SCANCODE_END
+	ScancodePageDown    = sdl.SCANCODE_PAGEDOWN
+	ScancodeRight       = sdl.SCANCODE_RIGHT
+	ScancodeLeft        = sdl.SCANCODE_LEFT
+	ScancodeDown        = sdl.SCANCODE_DOWN
+	ScancodeUp          = sdl.SCANCODE_UP
+
+	ScancodeNumlockClear = sdl.SCANCODE_NUMLOCKCLEAR
+	ScancodeKpDivide     = sdl.SCANCODE_KP_DIVIDE
+	ScancodeKpMultiply   = sdl.SCANCODE_KP_MULTIPLY
+	ScancodeKpMinus      = sdl.SCANCODE_KP_MINUS
+	ScancodeKpPlus       = sdl.SCANCODE_KP_PLUS
+	ScancodeKpEnter      = sdl.SCANCODE_KP_ENTER
+	ScancodeKp1          = sdl.SCANCODE_KP_1
+	ScancodeKp2          = sdl.SCANCODE_KP_2
+	ScancodeKp3          = sdl.SCANCODE_KP_3
+	ScancodeKp4          = sdl.SCANCODE_KP_4
+	ScancodeKp5          = sdl.SCANCODE_KP_5
+	ScancodeKp6          = sdl.SCANCODE_KP_6
+	ScancodeKp7          = sdl.SCANCODE_KP_7
+	ScancodeKp8          = sdl.SCANCODE_KP_8
+	ScancodeKp9          = sdl.SCANCODE_KP_9
+	ScancodeKp0          = sdl.SCANCODE_KP_0
+	ScancodeKpPeriod     = sdl.SCANCODE_KP_PERIOD
+
+	ScancodeNonUSBackslash = sdl.SCANCODE_NONUSBACKSLASH
+	ScancodeApplication    = sdl.SCANCODE_APPLICATION
+	ScancodePower          = sdl.SCANCODE_POWER
+	ScancodeKpEquals       = sdl.SCANCODE_KP_EQUALS
+	ScancodeF13            = sdl.SCANCODE_F13
+	ScancodeF14            = sdl.SCANCODE_F14
+	ScancodeF15            = sdl.SCANCODE_F15
+	ScancodeF16            = sdl.SCANCODE_F16
+	ScancodeF17            = sdl.SCANCODE_F17
+	ScancodeF18            = sdl.SCANCODE_F18
+	ScancodeF19            = sdl.SCANCODE_F19
+	ScancodeF20            = sdl.SCANCODE_F20
+	ScancodeF21            = sdl.SCANCODE_F21
+	ScancodeF22            = sdl.SCANCODE_F22
+	ScancodeF23            = sdl.SCANCODE_F23
+	ScancodeF24            = sdl.SCANCODE_F24
+	ScancodeExecute        = sdl.SCANCODE_EXECUTE
+	ScancodeHelp           = sdl.SCANCODE_HELP
+	ScancodeMenu           = sdl.SCANCODE_MENU
+	ScancodeSelect         = sdl.SCANCODE_SELECT
+	ScancodeStop           = sdl.SCANCODE_STOP
+	ScancodeAgain          = sdl.SCANCODE_AGAIN
+	ScancodeUndo           = sdl.SCANCODE_UNDO
+	ScancodeCut            = sdl.SCANCODE_CUT
+	ScancodeCopy           = sdl.SCANCODE_COPY
+	ScancodePaste          = sdl.SCANCODE_PASTE
+	ScancodeFind           = sdl.SCANCODE_FIND
+	ScancodeMute           = sdl.SCANCODE_MUTE
+	ScancodeVolumeUp       = sdl.SCANCODE_VOLUMEUP
+	ScancodeVolumeDown     = sdl.SCANCODE_VOLUMEDOWN
+	ScancodeKpComma        = sdl.SCANCODE_KP_COMMA
+	ScancodeKpEqualsAS400  = sdl.SCANCODE_KP_EQUALSAS400
+
+	ScancodeLCtrl  = sdl.SCANCODE_LCTRL
+	ScancodeLShift = sdl.SCANCODE_LSHIFT
+	ScancodeLAlt   = sdl.SCANCODE_LALT
+	ScancodeLGui   = sdl.SCANCODE_LGUI
+	ScancodeRCtrl  = sdl.SCANCODE_RCTRL
+	ScancodeRShift = sdl.SCANCODE_RSHIFT
+	ScancodeRAlt   = sdl.SCANCODE_RALT
+	ScancodeRGui   = sdl.SCANCODE_RGUI
+
+	ScancodeMode = sdl.SCANCODE_MODE
+)
@@ -22,6 +22,8 @@ func Loop(cfg Config, lf LoopFunc) error {
 
 	input := newSdlInput(window)
 	output := newSdlOutput(window, renderer)
+	defer output.cache.destroy()
+	defer output.freeCursor()
 
 	timer := time.Now()
 
@@ -53,6 +55,8 @@ func Loop(cfg Config, lf LoopFunc) error {
 			return nil
 		}
 
+		// canvases may have left the renderer drawing to them
+		output.bind()
 		renderer.Present()
 
 		if framerate != nil {
@@ -0,0 +1,110 @@
+package gogame
+
+import "math"
+
+// Matrix is an affine transformation of 2D space: any combination of moving, rotating,
+// scaling and skewing. A point (x, y) is transformed to
+//
+//	(m[0]*x + m[2]*y + m[4], m[1]*x + m[3]*y + m[5])
+//
+// The methods of a matrix return a new matrix with another transformation applied after it,
+// so transformations are chained in the order they are written:
+//
+//	m := IM.Scaled(Vec{}, Vec{X: 2, Y: 2}).Rotated(Vec{}, math.Pi/2).Moved(Vec{X: 100, Y: 0})
+type Matrix [6]float64
+
+// IM is the identity matrix, which doesn't transform anything.
+var IM = Matrix{1, 0, 0, 1, 0, 0}
+
+// Moved returns the matrix moving everything by delta afterwards.
+func (m Matrix) Moved(delta Vec) Matrix {
+	m[4] += delta.X
+	m[5] += delta.Y
+	return m
+}
+
+// Scaled returns the matrix scaling everything around a point afterwards, by a different
+// amount on each axis. Negative scale flips the axis.
+func (m Matrix) Scaled(around Vec, scale Vec) Matrix {
+	return m.Moved(around.M(-1)).Chained(Matrix{scale.X, 0, 0, scale.Y, 0, 0}).Moved(around)
+}
+
+// Rotated returns the matrix rotating everything around a point afterwards. Angle is in
+// radians, positive angles rotate clockwise on the screen.
+func (m Matrix) Rotated(around Vec, angle float64) Matrix {
+	sin, cos := math.Sincos(angle)
+	return m.Moved(around.M(-1)).Chained(Matrix{cos, sin, -sin, cos, 0, 0}).Moved(around)
+}
+
+// Chained returns the matrix applying the transformation of next after the one of m.
+func (m Matrix) Chained(next Matrix) Matrix {
+	return Matrix{
+		next[0]*m[0] + next[2]*m[1],
+		next[1]*m[0] + next[3]*m[1],
+		next[0]*m[2] + next[2]*m[3],
+		next[1]*m[2] + next[3]*m[3],
+		next[0]*m[4] + next[2]*m[5] + next[4],
+		next[1]*m[4] + next[3]*m[5] + next[5],
+	}
+}
+
+// Project transforms a point with the matrix.
+func (m Matrix) Project(u Vec) Vec {
+	return Vec{
+		X: m[0]*u.X + m[2]*u.Y + m[4],
+		Y: m[1]*u.X + m[3]*u.Y + m[5],
+	}
+}
+
+// Unproject transforms a point with the inverse of the matrix, so that
+// m.Unproject(m.Project(u)) == u. A matrix which squashes everything onto a line or a point
+// can't be inverted and the result is not a number.
+func (m Matrix) Unproject(u Vec) Vec {
+	det := m.det()
+	u = u.S(Vec{X: m[4], Y: m[5]})
+	return Vec{
+		X: (m[3]*u.X - m[2]*u.Y) / det,
+		Y: (m[0]*u.Y - m[1]*u.X) / det,
+	}
+}
+
+// det returns the determinant of the matrix: how many times it scales areas. It's negative if
+// the matrix mirrors.
+func (m Matrix) det() float64 {
+	return m[0]*m[3] - m[1]*m[2]
+}
+
+// axisAligned checks if the matrix only moves and scales, so that rectangles stay parallel
+// with the axes.
+func (m Matrix) axisAligned() bool {
+	return m[1] == 0 && m[2] == 0
+}
+
+// conformal checks if the matrix keeps shapes as they are (it only moves, rotates, mirrors and
+// scales the same on both axes), so that circles stay circles.
+func (m Matrix) conformal() bool {
+	return m[0] == m[3] && m[1] == -m[2] || m[0] == -m[3] && m[1] == m[2]
+}
+
+// projectBounds returns the smallest rectangle parallel with the axes containing a projected
+// rectangle.
+func (m Matrix) projectBounds(rect Rect) Rect {
+	return bounds(m.Project, rect)
+}
+
+// unprojectBounds returns the smallest rectangle parallel with the axes containing an
+// unprojected rectangle.
+func (m Matrix) unprojectBounds(rect Rect) Rect {
+	return bounds(m.Unproject, rect)
+}
+
+func bounds(f func(Vec) Vec, rect Rect) Rect {
+	points := rectPoints(rect)
+	min, max := f(points[0]), f(points[0])
+	for _, p := range points[1:] {
+		p = f(p)
+		min = Vec{X: math.Min(min.X, p.X), Y: math.Min(min.Y, p.Y)}
+		max = Vec{X: math.Max(max.X, p.X), Y: math.Max(max.Y, p.Y)}
+	}
+	return Rect{X: min.X, Y: min.Y, W: max.X - min.X, H: max.Y - min.Y}
+}
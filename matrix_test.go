@@ -0,0 +1,81 @@
+package gogame
+
+import (
+	"math"
+	"testing"
+)
+
+func TestMatrixProject(t *testing.T) {
+	p := Vec{X: 1, Y: 2}
+
+	tests := []struct {
+		name string
+		m    Matrix
+		want Vec
+	}{
+		{"identity", IM, Vec{X: 1, Y: 2}},
+		{"moved", IM.Moved(Vec{X: 10, Y: -5}), Vec{X: 11, Y: -3}},
+		{"scaled", IM.Scaled(Vec{}, Vec{X: 2, Y: 3}), Vec{X: 2, Y: 6}},
+		{"scaled around", IM.Scaled(Vec{X: 1, Y: 1}, Vec{X: 2, Y: 2}), Vec{X: 1, Y: 3}},
+		{"flipped", IM.Scaled(Vec{}, Vec{X: -1, Y: 1}), Vec{X: -1, Y: 2}},
+		{"rotated", IM.Rotated(Vec{}, math.Pi/2), Vec{X: -2, Y: 1}},
+		{"rotated around", IM.Rotated(Vec{X: 1, Y: 0}, math.Pi), Vec{X: 1, Y: -2}},
+		// chained in the order they are written: scaled first, then moved
+		{"scaled then moved", IM.Scaled(Vec{}, Vec{X: 2, Y: 2}).Moved(Vec{X: 1, Y: 0}),
+			Vec{X: 3, Y: 4}},
+		{"moved then scaled", IM.Moved(Vec{X: 1, Y: 0}).Scaled(Vec{}, Vec{X: 2, Y: 2}),
+			Vec{X: 4, Y: 4}},
+		{"rotated then moved", IM.Rotated(Vec{}, math.Pi/2).Moved(Vec{X: 10, Y: 0}),
+			Vec{X: 8, Y: 1}},
+		{"chained", IM.Moved(Vec{X: 1, Y: 0}).Chained(IM.Scaled(Vec{}, Vec{X: 2, Y: 2})),
+			Vec{X: 4, Y: 4}},
+		{"chained the other way", IM.Scaled(Vec{}, Vec{X: 2, Y: 2}).Chained(IM.Moved(Vec{X: 1})),
+			Vec{X: 3, Y: 4}},
+		{"skewed", Matrix{1, 0, 1, 1, 0, 0}, Vec{X: 3, Y: 2}},
+	}
+	for _, test := range tests {
+		if got := test.m.Project(p); !vecsClose(got, test.want) {
+			t.Errorf("%s: got %v, want %v", test.name, got, test.want)
+		}
+		if got := test.m.Unproject(test.m.Project(p)); !vecsClose(got, p) {
+			t.Errorf("%s: unprojected back to %v, want %v", test.name, got, p)
+		}
+	}
+
+	if got := (Matrix{1, 1, 1, 1, 0, 0}).Unproject(p); !math.IsNaN(got.X) && !math.IsInf(got.X, 0) {
+		t.Errorf("squashed onto a line: got %v, want not a number", got)
+	}
+}
+
+func TestMatrixChecks(t *testing.T) {
+	tests := []struct {
+		name        string
+		m           Matrix
+		axisAligned bool
+		conformal   bool
+	}{
+		{"identity", IM, true, true},
+		{"moved and scaled", IM.Scaled(Vec{}, Vec{X: 2, Y: 2}).Moved(Vec{X: 5, Y: 5}), true, true},
+		{"scaled unevenly", IM.Scaled(Vec{}, Vec{X: 2, Y: 3}), true, false},
+		{"rotated", IM.Rotated(Vec{}, 1), false, true},
+		{"mirrored", IM.Scaled(Vec{}, Vec{X: -1, Y: 1}).Rotated(Vec{}, 1), false, true},
+		{"skewed", Matrix{1, 0, 1, 1, 0, 0}, false, false},
+	}
+	for _, test := range tests {
+		if got := test.m.axisAligned(); got != test.axisAligned {
+			t.Errorf("%s: got axis aligned %v, want %v", test.name, got, test.axisAligned)
+		}
+		if got := test.m.conformal(); got != test.conformal {
+			t.Errorf("%s: got conformal %v, want %v", test.name, got, test.conformal)
+		}
+	}
+
+	// the bounds of a rectangle rotated by 45 degrees around its center
+	m := IM.Rotated(Vec{X: 1, Y: 1}, math.Pi/4)
+	got := m.projectBounds(Rect{W: 2, H: 2})
+	want := Rect{X: 1 - math.Sqrt2, Y: 1 - math.Sqrt2, W: 2 * math.Sqrt2, H: 2 * math.Sqrt2}
+	if !vecsClose(Vec{X: got.X, Y: got.Y}, Vec{X: want.X, Y: want.Y}) ||
+		!vecsClose(Vec{X: got.W, Y: got.H}, Vec{X: want.W, Y: want.H}) {
+		t.Errorf("rotated bounds: got %v, want %v", got, want)
+	}
+}
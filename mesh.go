@@ -0,0 +1,44 @@
+package gogame
+
+// Mesh is a set of triangles with colored (and optionally textured) corners. Meshes are
+// useful for terrain, trails or deforming sprites. Draw them with DrawMesh.
+type Mesh struct {
+	// Vertices are the corners of the triangles.
+	Vertices []Vertex
+
+	// Indices lists the triangles, three indices into Vertices per triangle. If Indices is
+	// nil, every three consecutive vertices make a triangle.
+	Indices []int
+
+	// Picture is mapped onto the triangles according to the UVs of the vertices. If it's nil,
+	// the triangles are only colored.
+	Picture *Picture
+}
+
+// Vertex is a corner of a triangle in a mesh.
+type Vertex struct {
+	Pos Vec
+
+	// Color is the color of the corner. Colors are blended smoothly across the triangles.
+	// If the mesh has a picture, the picture is masked with the colors.
+	Color Color
+
+	// UV is the point of the picture at the corner, in pixels relative to the top-left corner
+	// of the picture. The rotation of the picture is ignored.
+	UV Vec
+}
+
+// PolygonMesh creates a mesh filling a polygon. The vertices are the corners of the polygon,
+// in order, with their colors and UVs. The polygon may be concave, but must not intersect
+// itself.
+func PolygonMesh(vertices []Vertex, pic *Picture) *Mesh {
+	points := make([]Vec, len(vertices))
+	for i := range vertices {
+		points[i] = vertices[i].Pos
+	}
+	return &Mesh{
+		Vertices: vertices,
+		Indices:  triangulate(points),
+		Picture:  pic,
+	}
+}
@@ -3,6 +3,7 @@ package gogame
 // Output combines all of the output methods together.
 type Output interface {
 	WindowOutput
+	CursorOutput
 	VideoOutput
 	AudioOutput
 }
@@ -20,6 +21,36 @@ type WindowOutput interface {
 	WindowResize(w, h int)
 }
 
+// CursorOutput lets you hide, confine and change the mouse cursor.
+type CursorOutput interface {
+	// CursorSetVisible shows the mouse cursor if visible is true. Otherwise hides it.
+	CursorSetVisible(visible bool)
+
+	// CursorSetGrabbed confines the mouse cursor to the window if grabbed is true.
+	CursorSetGrabbed(grabbed bool)
+
+	// CursorSetRelative turns relative mouse mode on or off. In relative mode, the cursor
+	// is hidden, it stays at the same position and only MouseDelta reports the mouse
+	// movement. This is useful for FPS-style aiming.
+	CursorSetRelative(relative bool)
+
+	// CursorSetPicture sets a picture as the mouse cursor. The point (hotX, hotY) of the
+	// picture (in pixels) is the one that clicks. Rotation of the picture is ignored.
+	// If pic is nil, the default cursor is restored.
+	CursorSetPicture(pic *Picture, hotX, hotY int)
+}
+
+// TargetCanvasOutput lets you create canvases drawn by the same renderer as the output. It's
+// implemented by the window, by canvases and by target canvases, but not by outputs which wrap
+// another one, such as Camera and Transform:
+//
+//	lights := ctx.Output.(gogame.TargetCanvasOutput).NewTargetCanvas(320, 240)
+type TargetCanvasOutput interface {
+	// NewTargetCanvas creates a canvas of the specified width and height drawn by the same
+	// renderer as the output. See TargetCanvas.
+	NewTargetCanvas(width, height int) *TargetCanvas
+}
+
 // VideoOutput lets you draw primitives and pictures.
 type VideoOutput interface {
 	// OutputRect returns the output rectangle.
@@ -30,7 +61,34 @@ type VideoOutput interface {
 	// Default mask is Color{R: 1, G: 1, B: 1, A: 1}.
 	SetMask(color Color)
 
-	// Clear fill whole video output with one color.
+	// SetBlendMode sets how every following draw call should be blended with what's already
+	// drawn. It applies to primitives, pictures and text alike, but not to Clear.
+	// Default blend mode is BlendAlpha.
+	SetBlendMode(mode BlendMode)
+
+	// SetAntialias turns smoothing of the edges of primitives on or off.
+	// Default is off.
+	SetAntialias(antialias bool)
+
+	// PushClip restricts every following draw call to a rectangle until the matching
+	// PopClip. Nested clips are intersected with the ones outside of them. The rectangle is
+	// rounded to whole pixels.
+	PushClip(rect Rect)
+
+	// PopClip removes the clip pushed last.
+	PopClip()
+
+	// PushViewport restricts drawing to a rectangle like PushClip, and also moves the origin
+	// of the coordinates to its top-left corner until the matching PopViewport. While a
+	// viewport is pushed, OutputRect returns the rectangle of the viewport at (0, 0).
+	// Clips and viewports share one stack, so they must be popped in reverse order.
+	PushViewport(rect Rect)
+
+	// PopViewport removes the viewport pushed last.
+	PopViewport()
+
+	// Clear fill whole video output with one color. If a clip is pushed, only the clipped
+	// rectangle is filled.
 	Clear(color Color)
 
 	// DrawPoint draws a single pixel of the specified color.
@@ -41,16 +99,74 @@ type VideoOutput interface {
 	DrawLine(a, b Vec, thickness float64, color Color)
 
 	// DrawPolygon draws a closed polygon from the supplied points.
-	// If the thickness is 0, the polygon will be filled.
+	// If the thickness is 0, the polygon will be filled. A filled polygon may be concave,
+	// but must not intersect itself, fill a Path for that.
 	DrawPolygon(points []Vec, thickness float64, color Color)
 
+	// DrawPolyline draws lines connecting the points with a stroke, which specifies their
+	// thickness, caps, joins and dashes. If closed is true, the last point is connected to
+	// the first one.
+	DrawPolyline(points []Vec, closed bool, stroke Stroke, color Color)
+
+	// DrawPath fills a path with a color.
+	DrawPath(path *Path, color Color)
+
 	// DrawRect draws a rectangle parallel with the axis of the coordinate system.
 	// If the thickness is 0, the rectangle will be filled.
 	DrawRect(rect Rect, thickness float64, color Color)
 
+	// DrawCircle draws a circle. If the thickness is 0, the circle will be filled.
+	DrawCircle(center Vec, radius, thickness float64, color Color)
+
+	// DrawEllipse draws an ellipse parallel with the axis of the coordinate system, with
+	// the horizontal and vertical radius in radius.X and radius.Y.
+	// If the thickness is 0, the ellipse will be filled.
+	DrawEllipse(center, radius Vec, thickness float64, color Color)
+
+	// DrawArc draws a part of the outline of an ellipse (use equal radii for a circle), going
+	// from angle start to angle end. Angles are in radians, 0 points to the right and
+	// positive angles go clockwise (just like rotation of pictures).
+	DrawArc(center, radius Vec, start, end, thickness float64, color Color)
+
+	// DrawPie draws a slice of an ellipse, going from angle start to angle end, just like
+	// DrawArc. If the thickness is 0, the slice will be filled.
+	DrawPie(center, radius Vec, start, end, thickness float64, color Color)
+
+	// DrawRoundedRect draws a rectangle with corners rounded by the radius.
+	// If the thickness is 0, the rectangle will be filled.
+	DrawRoundedRect(rect Rect, radius, thickness float64, color Color)
+
+	// DrawBezier draws a Bézier curve through the control points. Three points make
+	// a quadratic curve and four points make a cubic curve.
+	DrawBezier(points []Vec, thickness float64, color Color)
+
+	// DrawGradientPolygon fills a polygon with a gradient. The polygon may be concave, but
+	// must not intersect itself.
+	DrawGradientPolygon(points []Vec, gradient Gradient)
+
+	// DrawGradientRect fills a rectangle parallel with the axis of the coordinate system with
+	// a gradient.
+	DrawGradientRect(rect Rect, gradient Gradient)
+
+	// DrawMesh draws the triangles of a mesh.
+	DrawMesh(mesh *Mesh)
+
 	// DrawPicture draws a picture onto a rect. The picture will be
 	// stretched to fit the rectangle.
 	DrawPicture(rect Rect, pic *Picture)
+
+	// DrawPictureEx draws a picture onto a rect, just like DrawPicture, but transformed
+	// according to the options.
+	DrawPictureEx(rect Rect, pic *Picture, opts PictureOptions)
+
+	// DrawBatch draws all pictures of a batch in the order they were added, just as if they
+	// were drawn one by one. Consecutive pictures sharing the same underlying picture are
+	// drawn together, so sort the batch by spritesheet where the order doesn't matter.
+	DrawBatch(batch *Batch)
+
+	// DrawText draws text using a font. Pos is the top-left corner of the first line.
+	// Text can have multiple lines separated by '\n'.
+	DrawText(pos Vec, font Font, text string, color Color)
 }
 
 // AudioOutput lets you play sounds and music.
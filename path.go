@@ -0,0 +1,326 @@
+package gogame
+
+import (
+	"math"
+	"sort"
+)
+
+// FillRule specifies which parts of a path are inside when its contours overlap.
+type FillRule int
+
+// Enumeration of all fill rules.
+const (
+	// FillEvenOdd fills the parts surrounded by an odd number of contours, so a contour
+	// inside another one is a hole.
+	FillEvenOdd FillRule = iota
+
+	// FillNonZero fills the parts where the contours surrounding them don't cancel out,
+	// so a contour inside another one is only a hole if it goes in the opposite direction.
+	FillNonZero
+)
+
+// NewPath creates an empty path filled according to a fill rule.
+func NewPath(rule FillRule) *Path {
+	return &Path{rule: rule}
+}
+
+// Path is a shape made of closed contours, such as a polygon with holes, or text outlines.
+// Contours may be concave and may intersect each other or themselves. Draw a path with
+// DrawPath.
+//
+// A path is triangulated when it's drawn for the first time and the triangles are reused
+// until the path changes, so a static shape should be built only once.
+type Path struct {
+	rule     FillRule
+	contours [][]Vec
+	cache    *pathCache
+}
+
+type pathCache struct {
+	triangles []Vec   // three points per triangle
+	outlines  [][]Vec // contours going the way drawFringes needs
+}
+
+// MoveTo starts a new contour at a point. The previous contour is closed automatically.
+func (p *Path) MoveTo(point Vec) {
+	p.contours = append(p.contours, []Vec{point})
+	p.cache = nil
+}
+
+// LineTo adds a straight line from the end of the current contour to a point.
+func (p *Path) LineTo(point Vec) {
+	if len(p.contours) == 0 {
+		p.MoveTo(point)
+		return
+	}
+	last := len(p.contours) - 1
+	p.contours[last] = append(p.contours[last], point)
+	p.cache = nil
+}
+
+// QuadTo adds a quadratic Bézier curve from the end of the current contour to a point.
+func (p *Path) QuadTo(control, point Vec) {
+	p.curveTo(control, point)
+}
+
+// CubicTo adds a cubic Bézier curve from the end of the current contour to a point.
+func (p *Path) CubicTo(control1, control2, point Vec) {
+	p.curveTo(control1, control2, point)
+}
+
+func (p *Path) curveTo(points ...Vec) {
+	if len(p.contours) == 0 {
+		p.MoveTo(points[0])
+	}
+	last := len(p.contours) - 1
+	start := p.contours[last][len(p.contours[last])-1]
+	curve := bezierPoints(append([]Vec{start}, points...))
+	p.contours[last] = append(p.contours[last], curve[1:]...)
+	p.cache = nil
+}
+
+// AddPolygon adds a whole closed contour.
+func (p *Path) AddPolygon(points []Vec) {
+	p.contours = append(p.contours, append([]Vec(nil), points...))
+	p.cache = nil
+}
+
+// Contains checks if a point is inside of the path according to its fill rule.
+func (p *Path) Contains(point Vec) bool {
+	return insideWinding(winding(p.contours, point), p.rule)
+}
+
+// Triangles returns the triangles filling the path, three points per triangle.
+func (p *Path) Triangles() []Vec {
+	return p.triangulated().triangles
+}
+
+func (p *Path) triangulated() *pathCache {
+	if p.cache != nil {
+		return p.cache
+	}
+
+	p.cache = &pathCache{triangles: pathTriangles(p.contours, p.rule)}
+	for k, contour := range p.contours {
+		outline := positive(contour)
+		if len(outline) < 3 {
+			continue
+		}
+		// contours of holes have their outside inside the polygon
+		if p.outsideFilled(k) {
+			outline = reversed(outline)
+		}
+		p.cache.outlines = append(p.cache.outlines, outline)
+	}
+	return p.cache
+}
+
+// outsideFilled checks if the outside of a contour is filled along its first edge which isn't
+// horizontal. The two sides of the edge are told apart by the winding around its middle without
+// the edge itself, instead of by points just beside it, which would be too close for large
+// coordinates to tell apart.
+func (p *Path) outsideFilled(k int) bool {
+	contour := p.contours[k]
+	for i := range contour {
+		a, b := contour[i], contour[(i+1)%len(contour)]
+		if a.Y == b.Y {
+			continue // horizontal edges don't count in windings
+		}
+		middle := a.A(b).D(2)
+		right := 0
+		for l, other := range p.contours {
+			for j := range other {
+				if l != k || j != i {
+					right += edgeWinding(other[j], other[(j+1)%len(other)], middle)
+				}
+			}
+		}
+
+		// the edge itself only counts for the points on its left
+		left := right - 1
+		if b.Y > a.Y {
+			left = right + 1
+		}
+
+		// the outside of a contour going so that its area is positive is on the right of the
+		// edges going down
+		if (b.Y > a.Y) == (polygonArea(contour) > 0) {
+			return insideWinding(right, p.rule)
+		}
+		return insideWinding(left, p.rule)
+	}
+	return false
+}
+
+// mapped returns a copy of a path with all points (including the cached triangles) moved by
+// an affine function. If the function mirrors the path, flip needs to be true.
+func (p *Path) mapped(f func(Vec) Vec, flip bool) *Path {
+	mapAll := func(points []Vec) []Vec {
+		result := make([]Vec, len(points))
+		for i := range points {
+			result[i] = f(points[i])
+		}
+		return result
+	}
+
+	cache := p.triangulated()
+	q := &Path{
+		rule:  p.rule,
+		cache: &pathCache{triangles: mapAll(cache.triangles)},
+	}
+	for _, contour := range p.contours {
+		q.contours = append(q.contours, mapAll(contour))
+	}
+	for _, outline := range cache.outlines {
+		outline = mapAll(outline)
+		if flip {
+			outline = reversed(outline)
+		}
+		q.cache.outlines = append(q.cache.outlines, outline)
+	}
+	return q
+}
+
+// pathTriangles triangulates the inside of contours by cutting them into horizontal slabs at
+// every vertex and every intersection of edges. No edges cross within a slab, so the inside
+// of a slab is a row of trapezoids between pairs of edges. Unlike triangulate, it handles
+// contours which overlap or intersect according to a fill rule, but it's slow, so it only
+// runs when a path changes.
+func pathTriangles(contours [][]Vec, rule FillRule) []Vec {
+	var (
+		edges []pathEdge
+		ys    []float64
+	)
+	for _, contour := range contours {
+		for i := range contour {
+			a, b := contour[i], contour[(i+1)%len(contour)]
+			if a.Y == b.Y {
+				continue // horizontal edges don't bound any slab
+			}
+			edge := pathEdge{a, b, 1}
+			if a.Y > b.Y {
+				edge = pathEdge{b, a, -1}
+			}
+			edges = append(edges, edge)
+			ys = append(ys, a.Y, b.Y)
+		}
+	}
+	for i := range edges {
+		for j := i + 1; j < len(edges); j++ {
+			if y, ok := edges[i].intersectionY(edges[j]); ok {
+				ys = append(ys, y)
+			}
+		}
+	}
+	sort.Float64s(ys)
+
+	var (
+		triangles []Vec
+		active    []pathEdge
+	)
+	for k := 1; k < len(ys); k++ {
+		y0, y1 := ys[k-1], ys[k]
+		if y1-y0 < 1e-9 {
+			continue
+		}
+		middle := (y0 + y1) / 2
+
+		active = active[:0]
+		for _, e := range edges {
+			if e.top.Y <= middle && e.bottom.Y >= middle {
+				active = append(active, e)
+			}
+		}
+		sort.Slice(active, func(i, j int) bool { return active[i].x(middle) < active[j].x(middle) })
+
+		count := 0
+		for i := 0; i+1 < len(active); i++ {
+			count += active[i].dir
+			if !insideWinding(count, rule) {
+				continue
+			}
+			l0, l1 := Vec{X: active[i].x(y0), Y: y0}, Vec{X: active[i].x(y1), Y: y1}
+			r0, r1 := Vec{X: active[i+1].x(y0), Y: y0}, Vec{X: active[i+1].x(y1), Y: y1}
+			triangles = append(triangles, l0, r0, r1, l0, r1, l1)
+		}
+	}
+	return triangles
+}
+
+// pathEdge is an edge of a contour going from top to bottom. Dir is 1 if the contour goes
+// down along the edge and -1 if it goes up.
+type pathEdge struct {
+	top, bottom Vec
+	dir         int
+}
+
+// x returns the X coordinate of the edge at y.
+func (e pathEdge) x(y float64) float64 {
+	t := (y - e.top.Y) / (e.bottom.Y - e.top.Y)
+	return e.top.X + (e.bottom.X-e.top.X)*t
+}
+
+// intersectionY returns the Y coordinate where two edges cross, if they do.
+func (e pathEdge) intersectionY(f pathEdge) (float64, bool) {
+	top, bottom := math.Max(e.top.Y, f.top.Y), math.Min(e.bottom.Y, f.bottom.Y)
+	if top >= bottom {
+		return 0, false
+	}
+	// the difference of the X coordinates changes linearly, it crosses 0 at the intersection
+	d0, d1 := e.x(top)-f.x(top), e.x(bottom)-f.x(bottom)
+	if (d0 < 0) == (d1 < 0) || d0 == d1 {
+		return 0, false
+	}
+	return top + (bottom-top)*d0/(d0-d1), true
+}
+
+// winding returns how many times contours go around a point, counting the directions.
+func winding(contours [][]Vec, point Vec) int {
+	count := 0
+	for _, contour := range contours {
+		for i := range contour {
+			count += edgeWinding(contour[i], contour[(i+1)%len(contour)], point)
+		}
+	}
+	return count
+}
+
+// edgeWinding returns how much an edge adds to the winding around a point: one if it crosses
+// the ray going right from the point, with the sign of the direction.
+func edgeWinding(a, b, point Vec) int {
+	if (a.Y <= point.Y) == (b.Y <= point.Y) {
+		return 0
+	}
+	x := a.X + (point.Y-a.Y)*(b.X-a.X)/(b.Y-a.Y)
+	switch {
+	case x <= point.X:
+		return 0
+	case b.Y > a.Y:
+		return 1
+	default:
+		return -1
+	}
+}
+
+func insideWinding(count int, rule FillRule) bool {
+	if rule == FillNonZero {
+		return count != 0
+	}
+	return count%2 != 0
+}
+
+// positive returns the points of a polygon going so that its area is positive.
+func positive(points []Vec) []Vec {
+	if polygonArea(points) < 0 {
+		return reversed(points)
+	}
+	return points
+}
+
+func reversed(points []Vec) []Vec {
+	result := make([]Vec, len(points))
+	for i := range points {
+		result[len(points)-1-i] = points[i]
+	}
+	return result
+}
@@ -0,0 +1,137 @@
+package gogame
+
+import (
+	"math"
+	"testing"
+)
+
+func TestPathFill(t *testing.T) {
+	square := rectPoints(Rect{W: 10, H: 10})
+	hole := rectPoints(Rect{X: 2, Y: 2, W: 4, H: 4})
+	var star []Vec
+	for i := 0; i < 5; i++ {
+		angle := float64(i) * 4 * math.Pi / 5
+		star = append(star, Vec{X: 10 * math.Cos(angle), Y: 10 * math.Sin(angle)})
+	}
+
+	tests := []struct {
+		name     string
+		rule     FillRule
+		contours [][]Vec
+		inside   []Vec
+		outside  []Vec
+	}{
+		{"hole even-odd", FillEvenOdd, [][]Vec{square, hole},
+			[]Vec{{X: 1, Y: 1}, {X: 8, Y: 8}}, []Vec{{X: 4, Y: 4}, {X: 11, Y: 5}}},
+		{"hole going the same way non-zero", FillNonZero, [][]Vec{square, hole},
+			[]Vec{{X: 1, Y: 1}, {X: 4, Y: 4}}, []Vec{{X: 11, Y: 5}}},
+		{"hole going the other way non-zero", FillNonZero, [][]Vec{square, reversed(hole)},
+			[]Vec{{X: 1, Y: 1}}, []Vec{{X: 4, Y: 4}, {X: 11, Y: 5}}},
+		{"star even-odd", FillEvenOdd, [][]Vec{star},
+			[]Vec{{X: 5, Y: 0}}, []Vec{{X: 0, Y: 0}, {X: -9, Y: 0}}},
+		{"star non-zero", FillNonZero, [][]Vec{star},
+			[]Vec{{X: 5, Y: 0}, {X: 0, Y: 0}}, []Vec{{X: -9, Y: 0}}},
+	}
+
+	for _, test := range tests {
+		path := NewPath(test.rule)
+		for _, contour := range test.contours {
+			path.AddPolygon(contour)
+		}
+
+		for _, p := range test.inside {
+			if !path.Contains(p) {
+				t.Errorf("%s: %v: got outside, want inside", test.name, p)
+			}
+			if !inTriangles(path.Triangles(), p) {
+				t.Errorf("%s: %v: got not covered by the triangles, want covered", test.name, p)
+			}
+		}
+		for _, p := range test.outside {
+			if path.Contains(p) {
+				t.Errorf("%s: %v: got inside, want outside", test.name, p)
+			}
+			if inTriangles(path.Triangles(), p) {
+				t.Errorf("%s: %v: got covered by the triangles, want not covered", test.name, p)
+			}
+		}
+	}
+}
+
+func TestPathTrianglesDontOverlap(t *testing.T) {
+	path := NewPath(FillNonZero)
+	path.AddPolygon(rectPoints(Rect{W: 10, H: 10}))
+	path.AddPolygon(rectPoints(Rect{X: 5, Y: 5, W: 10, H: 10}))
+
+	area := 0.0
+	triangles := path.Triangles()
+	for i := 0; i < len(triangles); i += 3 {
+		area += math.Abs(polygonArea(triangles[i : i+3]))
+	}
+	if math.Abs(area-175) > 1e-9 {
+		t.Errorf("two overlapping squares: got triangles with area %v, want 175", area)
+	}
+
+	path.AddPolygon(rectPoints(Rect{X: 20, Y: 0, W: 1, H: 1}))
+	if got := len(path.Triangles()); got == len(triangles) {
+		t.Error("adding a contour: got the cached triangles, want new ones")
+	}
+}
+
+func TestPathOutlines(t *testing.T) {
+	square := rectPoints(Rect{W: 10, H: 10})
+	hole := rectPoints(Rect{X: 2, Y: 2, W: 4, H: 4})
+	separate := rectPoints(Rect{X: 20, W: 4, H: 4})
+
+	tests := []struct {
+		name     string
+		rule     FillRule
+		at       Vec // where the contours are moved, far away ones have coarse coordinates
+		inner    []Vec
+		reversed bool // whether the outline of the inner contour is reversed
+	}{
+		{"hole even-odd", FillEvenOdd, Vec{}, hole, true},
+		{"far away hole even-odd", FillEvenOdd, Vec{X: 1e12, Y: -1e12}, hole, true},
+		{"far away hole non-zero", FillNonZero, Vec{X: -1e12, Y: 1e12}, reversed(hole), true},
+		{"far away separate", FillNonZero, Vec{X: 1e12, Y: 1e12}, separate, false},
+	}
+	for _, test := range tests {
+		move := func(points []Vec) []Vec {
+			moved := make([]Vec, len(points))
+			for i := range points {
+				moved[i] = points[i].A(test.at)
+			}
+			return moved
+		}
+		path := NewPath(test.rule)
+		path.AddPolygon(move(square))
+		path.AddPolygon(move(test.inner))
+
+		// outlines go so that the filled side is on the left, like positive polygons
+		outlines := path.triangulated().outlines
+		if len(outlines) != 2 {
+			t.Errorf("%s: got %d outlines, want 2", test.name, len(outlines))
+			continue
+		}
+		if polygonArea(outlines[0]) <= 0 {
+			t.Errorf("%s: got the outer outline reversed, want it positive", test.name)
+		}
+		if got := polygonArea(outlines[1]) < 0; got != test.reversed {
+			t.Errorf("%s: got the inner outline reversed %v, want %v", test.name, got,
+				test.reversed)
+		}
+	}
+}
+
+func inTriangles(triangles []Vec, p Vec) bool {
+	for i := 0; i+2 < len(triangles); i += 3 {
+		a, b, c := triangles[i], triangles[i+1], triangles[i+2]
+		if polygonArea([]Vec{a, b, c}) < 0 {
+			a, c = c, a
+		}
+		if inTriangle(p, a, b, c) {
+			return true
+		}
+	}
+	return false
+}
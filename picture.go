@@ -3,8 +3,8 @@ package gogame
 import (
 	"fmt"
 
+	"github.com/veandco/go-sdl2/img"
 	"github.com/veandco/go-sdl2/sdl"
-	"github.com/veandco/go-sdl2/sdl_image"
 )
 
 // LoadPicture loads a picture from a file stored at the specified path.
@@ -18,7 +18,7 @@ func LoadPicture(path string) (*Picture, error) {
 	if err != nil {
 		return nil, fmt.Errorf("failed to load picture: %s", path)
 	}
-	pic.surface.Flags |= staticSurface
+	surfaceFlags[pic.surface] |= staticSurface
 	pic.rect = sdl.Rect{X: 0, Y: 0, W: pic.surface.W, H: pic.surface.H}
 	return &pic, nil
 }
@@ -28,6 +28,25 @@ type Picture struct {
 	surface *sdl.Surface
 	rect    sdl.Rect
 	angle   float64
+	canvas  *TargetCanvas // if set, the surface is only up to date after reading back
+}
+
+// PictureOptions specifies how DrawPictureEx transforms a picture.
+type PictureOptions struct {
+	// Pivot is the point the picture is rotated around, relative to the top-left corner of
+	// the rect the picture is drawn onto. DrawPicture rotates around the center of the rect.
+	Pivot Vec
+
+	// Angle is added to the rotation of the picture. Angle is in radians.
+	Angle float64
+
+	// FlipX flips the picture horizontally and FlipY vertically. Pictures are flipped within
+	// the rect before they are rotated.
+	FlipX, FlipY bool
+
+	// Tint is a color the picture is masked with (on top of the mask of the output).
+	// Zero tint means no tint.
+	Tint Color
 }
 
 // Size returns the width and height of a picture in pixels.
@@ -47,7 +66,8 @@ func (p *Picture) Slice(x, y, w, h int) *Picture {
 			W: int32(w),
 			H: int32(h),
 		},
-		angle: p.angle,
+		angle:  p.angle,
+		canvas: p.canvas,
 	}
 }
 
@@ -59,6 +79,7 @@ func (p *Picture) Rotated(angle float64) *Picture {
 		surface: p.surface,
 		rect:    p.rect,
 		angle:   p.angle + angle,
+		canvas:  p.canvas,
 	}
 }
 
@@ -66,8 +87,11 @@ func (p *Picture) Rotated(angle float64) *Picture {
 // This is particularly useful when dealing with canvases, since this copy can be rendered
 // more effeciently than the internal picture of a canvas.
 func (p *Picture) Copy() *Picture {
+	if p.canvas != nil {
+		p.canvas.readBack()
+	}
 	surface, err := sdl.CreateRGBSurface(
-		p.surface.Flags,
+		0,
 		p.surface.W,
 		p.surface.H,
 		int32(p.surface.Format.BitsPerPixel),
@@ -81,8 +105,12 @@ func (p *Picture) Copy() *Picture {
 		panic(fmt.Errorf("failed to copy picture: %s", err))
 	}
 
+	// copy the pixels as they are, blending onto an empty surface would darken them
+	blendMode, _ := p.surface.GetBlendMode()
+	p.surface.SetBlendMode(sdl.BLENDMODE_NONE)
 	p.surface.Blit(nil, surface, nil)
-	surface.Flags |= staticSurface
+	p.surface.SetBlendMode(blendMode)
+	surfaceFlags[surface] |= staticSurface | surfaceFlags[p.surface]&premultipliedSurface
 
 	return &Picture{
 		surface: surface,
@@ -91,6 +119,90 @@ func (p *Picture) Copy() *Picture {
 	}
 }
 
+// cutSurface copies the rectangle of a picture into a new surface of the same format.
+// The caller is responsible for freeing the returned surface.
+func (p *Picture) cutSurface() (*sdl.Surface, error) {
+	if p.canvas != nil {
+		p.canvas.readBack()
+	}
+	surface, err := sdl.CreateRGBSurface(
+		0,
+		p.rect.W,
+		p.rect.H,
+		int32(p.surface.Format.BitsPerPixel),
+		p.surface.Format.Rmask,
+		p.surface.Format.Gmask,
+		p.surface.Format.Bmask,
+		p.surface.Format.Amask,
+	)
+	if err != nil {
+		return nil, err
+	}
+
+	// copy the pixels as they are, blending onto an empty surface would darken them
+	blendMode, _ := p.surface.GetBlendMode()
+	p.surface.SetBlendMode(sdl.BLENDMODE_NONE)
+	rect := p.rect
+	p.surface.Blit(&rect, surface, nil)
+	p.surface.SetBlendMode(blendMode)
+
+	// the new surface is not a canvas, it needs straight colors
+	if surfaceFlags[p.surface]&premultipliedSurface != 0 {
+		unpremultiply(surface)
+	}
+
+	return surface, nil
+}
+
+// unpremultiply divides the color channels of a 32-bit surface with the alpha in the last
+// byte of each pixel (such as the surfaces of canvases) by the alpha.
+func unpremultiply(surface *sdl.Surface) {
+	pixels := surface.Pixels()
+	for y := 0; y < int(surface.H); y++ {
+		row := pixels[y*int(surface.Pitch):]
+		for x := 0; x < int(surface.W); x++ {
+			pixel := row[4*x : 4*x+4]
+			a := int(pixel[3])
+			if a == 0 || a == 255 {
+				continue
+			}
+			for i := 0; i < 3; i++ {
+				c := int(pixel[i]) * 255 / a
+				if c > 255 {
+					c = 255
+				}
+				pixel[i] = byte(c)
+			}
+		}
+	}
+}
+
+// Flags of surfaces, kept in surfaceFlags.
 const (
+	// staticSurface marks surfaces which only change with their version in surfaceVersions,
+	// so that their textures can be kept
 	staticSurface = 1 << iota
+
+	// premultipliedSurface marks surfaces of canvases, which have colors premultiplied by alpha
+	premultipliedSurface
 )
+
+// surfaceFlags holds the flags of surfaces. SDL surfaces have their own flags, but go-sdl2
+// doesn't allow setting them, and SDL uses them itself.
+var surfaceFlags = make(map[*sdl.Surface]int)
+
+// freeSurface frees a surface which may have been drawn, destroying its textures in all
+// outputs, so that they don't leak and a new surface at the same address doesn't get them.
+func freeSurface(surface *sdl.Surface) {
+	for cache := range textureCaches {
+		cache.evict(surface)
+	}
+	delete(surfaceFlags, surface)
+	delete(surfaceVersions, surface)
+	surface.Free()
+}
+
+// surfaceVersions counts modifications of static surfaces (such as glyph atlases), so that
+// outputs know when to re-upload their textures. Surfaces which are never modified are not
+// in the map.
+var surfaceVersions = make(map[*sdl.Surface]int)
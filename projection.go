@@ -0,0 +1,317 @@
+package gogame
+
+import "math"
+
+// projection draws everything transformed by a matrix using an underlying video output. It
+// implements the drawing of Camera and Transform. Sizes which aren't positions (thickness of
+// lines and strokes) aren't transformed.
+//
+// Matrices which only move and scale keep rectangles, circles and pictures as they are and only
+// project their positions and sizes. Other matrices turn them into polygons and meshes.
+type projection struct {
+	m   Matrix
+	out VideoOutput
+}
+
+func (p projection) projectAll(points []Vec) []Vec {
+	projected := make([]Vec, len(points))
+	for i := range points {
+		projected[i] = p.m.Project(points[i])
+	}
+	return projected
+}
+
+// projectDetailed projects the points of a curved shape. The shape is created at the scale of
+// the matrix and then scaled back, so that the curves have enough points once projected.
+func (p projection) projectDetailed(shape func(scale float64) []Vec) []Vec {
+	scale := math.Sqrt(math.Abs(p.m.det()))
+	if scale == 0 {
+		scale = 1
+	}
+	points := shape(scale)
+	for i := range points {
+		points[i] = p.m.Project(points[i].D(scale))
+	}
+	return points
+}
+
+// zoom returns the scale on each axis of an axis-aligned matrix.
+func (p projection) zoom() Vec {
+	return Vec{X: p.m[0], Y: p.m[3]}
+}
+
+// flips checks if the matrix mirrors, which reverses the order of points of polygons.
+func (p projection) flips() bool {
+	return p.m.det() < 0
+}
+
+func (p projection) PushClip(rect Rect) {
+	p.out.PushClip(p.m.projectBounds(rect))
+}
+
+func (p projection) DrawPoint(point Vec, color Color) {
+	p.out.DrawPoint(p.m.Project(point), color)
+}
+
+func (p projection) DrawLine(a, b Vec, thickness float64, color Color) {
+	p.out.DrawLine(p.m.Project(a), p.m.Project(b), thickness, color)
+}
+
+func (p projection) DrawPolygon(points []Vec, thickness float64, color Color) {
+	p.out.DrawPolygon(p.projectAll(points), thickness, color)
+}
+
+func (p projection) DrawPolyline(points []Vec, closed bool, stroke Stroke, color Color) {
+	p.out.DrawPolyline(p.projectAll(points), closed, stroke, color)
+}
+
+// DrawPath projects the cached triangles of the path too, so it isn't triangulated again.
+func (p projection) DrawPath(path *Path, color Color) {
+	p.out.DrawPath(path.mapped(p.m.Project, p.flips()), color)
+}
+
+func (p projection) DrawRect(rect Rect, thickness float64, color Color) {
+	if p.m.axisAligned() {
+		p.out.DrawRect(p.m.projectBounds(rect), thickness, color)
+		return
+	}
+	p.out.DrawPolygon(p.projectAll(rectPoints(rect)), thickness, color)
+}
+
+// DrawPictureEx flips the picture under a negative zoom and mirrors its rotation. If the matrix
+// rotates or skews, the picture is drawn as a mesh.
+func (p projection) DrawPictureEx(rect Rect, pic *Picture, opts PictureOptions) {
+	if !p.m.axisAligned() {
+		p.out.DrawMesh(p.pictureMesh(rect, pic, opts))
+		return
+	}
+
+	zoom := p.zoom()
+	pivot := p.m.Project(rect.Pos().A(opts.Pivot))
+	rect = p.m.projectBounds(rect)
+	opts.Pivot = pivot.S(rect.Pos())
+
+	if zoom.X < 0 {
+		opts.FlipX = !opts.FlipX
+	}
+	if zoom.Y < 0 {
+		opts.FlipY = !opts.FlipY
+	}
+	if (zoom.X < 0) != (zoom.Y < 0) {
+		// mirroring along one axis reverses the whole rotation, including the picture's own
+		opts.Angle = -opts.Angle - 2*pic.angle
+	}
+
+	p.out.DrawPictureEx(rect, pic, opts)
+}
+
+// pictureMesh creates a projected mesh of a picture drawn like with DrawPictureEx.
+func (p projection) pictureMesh(rect Rect, pic *Picture, opts PictureOptions) *Mesh {
+	w, h := float64(pic.rect.W), float64(pic.rect.H)
+	uvs := []Vec{{0, 0}, {w, 0}, {w, h}, {0, h}}
+	for i := range uvs {
+		if opts.FlipX {
+			uvs[i].X = w - uvs[i].X
+		}
+		if opts.FlipY {
+			uvs[i].Y = h - uvs[i].Y
+		}
+	}
+
+	color := Color{1, 1, 1, 1}
+	if opts.Tint != (Color{}) {
+		color = opts.Tint
+	}
+
+	pivot := rect.Pos().A(opts.Pivot)
+	angle := pic.angle + opts.Angle
+	mesh := &Mesh{Indices: []int{0, 1, 2, 0, 2, 3}, Picture: pic}
+	for i, corner := range rectPoints(rect) {
+		pos := p.m.Project(corner.S(pivot).Rotated(angle).A(pivot))
+		mesh.Vertices = append(mesh.Vertices, Vertex{Pos: pos, Color: color, UV: uvs[i]})
+	}
+	return mesh
+}
+
+// DrawBatch projects the rectangles of the sprites and mirrors the sprites along with the matrix,
+// like DrawPictureEx. If the matrix rotates or skews, the sprites are drawn as meshes.
+func (p projection) DrawBatch(batch *Batch) {
+	if !p.m.axisAligned() {
+		sprites := batch.sprites
+		for len(sprites) > 0 {
+			// like in the outputs, consecutive sprites of the same surface go together
+			run := 1
+			for run < len(sprites) && sprites[run].pic.surface == sprites[0].pic.surface {
+				run++
+			}
+			if mesh := p.spritesMesh(sprites[:run]); len(mesh.Indices) > 0 {
+				p.out.DrawMesh(mesh)
+			}
+			sprites = sprites[run:]
+		}
+		return
+	}
+
+	zoom := p.zoom()
+	projected := &Batch{sprites: make([]batchSprite, len(batch.sprites))}
+	for i, sprite := range batch.sprites {
+		sprite.rect = p.m.projectBounds(sprite.rect)
+		sprite.flipX = sprite.flipX != (zoom.X < 0)
+		sprite.flipY = sprite.flipY != (zoom.Y < 0)
+		if (zoom.X < 0) != (zoom.Y < 0) {
+			sprite.angle = -sprite.angle
+		}
+		projected.sprites[i] = sprite
+	}
+	p.out.DrawBatch(projected)
+}
+
+// spritesMesh merges sprites of one surface into a single mesh. The UVs are relative to the
+// picture of the first sprite, so that the mesh can use it for all of them.
+func (p projection) spritesMesh(sprites []batchSprite) *Mesh {
+	mesh := &Mesh{Picture: sprites[0].pic}
+	origin := sprites[0].pic.rect
+	for _, sprite := range sprites {
+		opts := PictureOptions{
+			Pivot: sprite.rect.Size().D(2),
+			Angle: sprite.angle - sprite.pic.angle,
+			FlipX: sprite.flipX,
+			FlipY: sprite.flipY,
+			Tint:  sprite.color,
+		}
+		if opts.Tint == (Color{}) {
+			continue // zero tint would mean no tint
+		}
+		offset := Vec{
+			X: float64(sprite.pic.rect.X - origin.X),
+			Y: float64(sprite.pic.rect.Y - origin.Y),
+		}
+		base := len(mesh.Vertices)
+		sm := p.pictureMesh(sprite.rect, sprite.pic, opts)
+		for _, v := range sm.Vertices {
+			v.UV = v.UV.A(offset)
+			mesh.Vertices = append(mesh.Vertices, v)
+		}
+		for _, i := range sm.Indices {
+			mesh.Indices = append(mesh.Indices, base+i)
+		}
+	}
+	return mesh
+}
+
+// DrawText scales the text by the zoom. If the matrix mirrors, rotates or skews, the glyphs are
+// drawn one by one like pictures, so that they are mirrored and rotated along with the text.
+func (p projection) DrawText(pos Vec, font Font, text string, color Color) {
+	if zoom := p.zoom(); p.m.axisAligned() && zoom.X > 0 && zoom.Y > 0 {
+		p.out.DrawText(p.m.Project(pos), scaledFont{font, zoom}, text, color)
+		return
+	}
+	if color == (Color{}) {
+		return // zero tint would mean no tint
+	}
+	forEachGlyph(pos, font, text, func(rect Rect, pic *Picture) {
+		p.DrawPictureEx(rect, pic, PictureOptions{Pivot: rect.Size().D(2), Tint: color})
+	})
+}
+
+// projectRadius transforms a radius (or any size) with an axis-aligned matrix.
+func (p projection) projectRadius(radius Vec) Vec {
+	zoom := p.zoom()
+	return Vec{X: math.Abs(radius.X * zoom.X), Y: math.Abs(radius.Y * zoom.Y)}
+}
+
+// projectAngles transforms a range of angles with an axis-aligned matrix. Negative zoom
+// mirrors the angles.
+func (p projection) projectAngles(start, end float64) (float64, float64) {
+	zoom := p.zoom()
+	if zoom.X < 0 {
+		start, end = math.Pi-start, math.Pi-end
+	}
+	if zoom.Y < 0 {
+		start, end = -start, -end
+	}
+	return start, end
+}
+
+// DrawCircle draws an ellipse if the matrix stretches the circle.
+func (p projection) DrawCircle(center Vec, radius, thickness float64, color Color) {
+	if p.m.conformal() {
+		scale := math.Sqrt(math.Abs(p.m.det()))
+		p.out.DrawCircle(p.m.Project(center), radius*scale, thickness, color)
+		return
+	}
+	p.DrawEllipse(center, Vec{X: radius, Y: radius}, thickness, color)
+}
+
+func (p projection) DrawEllipse(center, radius Vec, thickness float64, color Color) {
+	if p.m.axisAligned() {
+		p.out.DrawEllipse(p.m.Project(center), p.projectRadius(radius), thickness, color)
+		return
+	}
+	points := p.projectDetailed(func(scale float64) []Vec {
+		return ellipsePoints(center.M(scale), radius.M(scale))
+	})
+	p.out.DrawPolygon(points, thickness, color)
+}
+
+func (p projection) DrawArc(center, radius Vec, start, end, thickness float64, color Color) {
+	if p.m.axisAligned() {
+		start, end := p.projectAngles(start, end)
+		p.out.DrawArc(p.m.Project(center), p.projectRadius(radius), start, end, thickness, color)
+		return
+	}
+	points := p.projectDetailed(func(scale float64) []Vec {
+		return arcPoints(center.M(scale), radius.M(scale), start, end)
+	})
+	p.out.DrawPolyline(points, false, thickStroke(thickness), color)
+}
+
+func (p projection) DrawPie(center, radius Vec, start, end, thickness float64, color Color) {
+	if p.m.axisAligned() {
+		start, end := p.projectAngles(start, end)
+		p.out.DrawPie(p.m.Project(center), p.projectRadius(radius), start, end, thickness, color)
+		return
+	}
+	points := p.projectDetailed(func(scale float64) []Vec {
+		arc := arcPoints(center.M(scale), radius.M(scale), start, end)
+		return append([]Vec{center.M(scale)}, arc...)
+	})
+	p.out.DrawPolygon(points, thickness, color)
+}
+
+// DrawRoundedRect draws the corners as parts of ellipses if the zoom differs on each axis.
+func (p projection) DrawRoundedRect(rect Rect, radius, thickness float64, color Color) {
+	r := p.projectRadius(Vec{X: radius, Y: radius})
+	if p.m.axisAligned() && r.X == r.Y {
+		p.out.DrawRoundedRect(p.m.projectBounds(rect), r.X, thickness, color)
+		return
+	}
+	points := p.projectDetailed(func(scale float64) []Vec {
+		scaled := Rect{X: rect.X * scale, Y: rect.Y * scale, W: rect.W * scale, H: rect.H * scale}
+		return roundedRectPoints(scaled, radius*scale)
+	})
+	p.out.DrawPolygon(points, thickness, color)
+}
+
+// DrawBezier projects the control points, which gives the same curve as projecting the curve.
+func (p projection) DrawBezier(points []Vec, thickness float64, color Color) {
+	p.out.DrawBezier(p.projectAll(points), thickness, color)
+}
+
+func (p projection) DrawGradientPolygon(points []Vec, gradient Gradient) {
+	p.DrawMesh(gradientMesh(points, gradient))
+}
+
+func (p projection) DrawGradientRect(rect Rect, gradient Gradient) {
+	p.DrawMesh(gradientMesh(rectPoints(rect), gradient))
+}
+
+func (p projection) DrawMesh(mesh *Mesh) {
+	projected := *mesh
+	projected.Vertices = make([]Vertex, len(mesh.Vertices))
+	for i, v := range mesh.Vertices {
+		v.Pos = p.m.Project(v.Pos)
+		projected.Vertices[i] = v
+	}
+	p.out.DrawMesh(&projected)
+}
@@ -0,0 +1,281 @@
+package gogame
+
+import (
+	"math"
+	"reflect"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+// recorder is a video output recording what is drawn onto it. Other methods panic.
+type recorder struct {
+	VideoOutput
+	batches []*Batch
+	meshes  []*Mesh
+	texts   []string
+	rects   []Rect
+	options []PictureOptions
+}
+
+func (r *recorder) DrawBatch(batch *Batch) {
+	r.batches = append(r.batches, &Batch{sprites: append([]batchSprite(nil), batch.sprites...)})
+}
+
+func (r *recorder) DrawMesh(mesh *Mesh) { r.meshes = append(r.meshes, mesh) }
+
+func (r *recorder) DrawText(pos Vec, font Font, text string, color Color) {
+	r.texts = append(r.texts, text)
+}
+
+func (r *recorder) DrawPictureEx(rect Rect, pic *Picture, opts PictureOptions) {
+	r.rects, r.options = append(r.rects, rect), append(r.options, opts)
+}
+
+func TestProjectionDrawBatch(t *testing.T) {
+	pic := &Picture{rect: sdl.Rect{W: 4, H: 2}, angle: 0.5}
+	batch := &Batch{}
+	batch.Add(Rect{X: 1, Y: 1, W: 4, H: 2}, pic, Color{1, 1, 1, 1})
+
+	tests := []struct {
+		name         string
+		zoom         Vec
+		rect         Rect
+		flipX, flipY bool
+		angle        float64
+	}{
+		{"not mirrored", Vec{X: 2, Y: 2}, Rect{X: 2, Y: 2, W: 8, H: 4}, false, false, 0.5},
+		{"mirrored horizontally", Vec{X: -1, Y: 1}, Rect{X: -5, Y: 1, W: 4, H: 2}, true, false, -0.5},
+		{"mirrored vertically", Vec{X: 1, Y: -1}, Rect{X: 1, Y: -3, W: 4, H: 2}, false, true, -0.5},
+		{"turned around", Vec{X: -1, Y: -1}, Rect{X: -5, Y: -3, W: 4, H: 2}, true, true, 0.5},
+	}
+	for _, test := range tests {
+		out := &recorder{}
+		projection{IM.Scaled(Vec{}, test.zoom), out}.DrawBatch(batch)
+		if len(out.batches) != 1 || len(out.batches[0].sprites) != 1 {
+			t.Errorf("%s: got %d batches, want one sprite", test.name, len(out.batches))
+			continue
+		}
+		got := out.batches[0].sprites[0]
+		if got.rect != test.rect {
+			t.Errorf("%s: got rect %v, want %v", test.name, got.rect, test.rect)
+		}
+		if got.flipX != test.flipX || got.flipY != test.flipY {
+			t.Errorf("%s: got flips %v %v, want %v %v", test.name,
+				got.flipX, got.flipY, test.flipX, test.flipY)
+		}
+		if math.Abs(got.angle-test.angle) > 1e-9 {
+			t.Errorf("%s: got angle %v, want %v", test.name, got.angle, test.angle)
+		}
+	}
+}
+
+func TestProjectionDrawBatchRotated(t *testing.T) {
+	sheet, other := &sdl.Surface{}, &sdl.Surface{}
+	a := &Picture{surface: sheet, rect: sdl.Rect{X: 10, Y: 20, W: 4, H: 4}}
+	b := &Picture{surface: sheet, rect: sdl.Rect{X: 30, Y: 20, W: 4, H: 4}}
+	c := &Picture{surface: other, rect: sdl.Rect{W: 4, H: 4}}
+	white := Color{1, 1, 1, 1}
+
+	batch := &Batch{}
+	batch.Add(Rect{W: 4, H: 4}, a, white)
+	batch.Add(Rect{X: 10, W: 4, H: 4}, b, white)
+	batch.Add(Rect{X: 20, W: 4, H: 4}, b, Color{}) // zero color, not drawn
+	batch.Add(Rect{X: 30, W: 4, H: 4}, c, white)
+	batch.Add(Rect{X: 40, W: 4, H: 4}, a, white)
+
+	out := &recorder{}
+	projection{IM.Rotated(Vec{}, 1), out}.DrawBatch(batch)
+
+	// one mesh per run of sprites sharing a surface
+	wantSprites := []int{2, 1, 1}
+	if len(out.meshes) != len(wantSprites) {
+		t.Fatalf("got %d meshes, want %d", len(out.meshes), len(wantSprites))
+	}
+	for i, mesh := range out.meshes {
+		if len(mesh.Vertices) != 4*wantSprites[i] || len(mesh.Indices) != 6*wantSprites[i] {
+			t.Errorf("mesh %d: got %d vertices and %d indices, want %d sprites", i,
+				len(mesh.Vertices), len(mesh.Indices), wantSprites[i])
+		}
+	}
+
+	// the second sprite of the first mesh is mapped relative to the first picture
+	merged := out.meshes[0]
+	if merged.Picture != a {
+		t.Errorf("got picture %v, want the first one", merged.Picture)
+	}
+	if got, want := merged.Vertices[4].UV, (Vec{X: 20, Y: 0}); got != want {
+		t.Errorf("second sprite: got UV %v, want %v", got, want)
+	}
+	if got, want := merged.Indices[6:], []int{4, 5, 6, 4, 6, 7}; !reflect.DeepEqual(got, want) {
+		t.Errorf("second sprite: got indices %v, want %v", got, want)
+	}
+}
+
+// picFont is monoFont with a picture for every rune, so that the glyphs get drawn.
+type picFont struct{ monoFont }
+
+func (picFont) Glyph(r rune) (*Picture, Rect, float64) {
+	return &Picture{rect: sdl.Rect{W: 10, H: 20}}, Rect{W: 10, H: 20}, 10
+}
+
+func TestProjectionDrawText(t *testing.T) {
+	tests := []struct {
+		name  string
+		zoom  Vec
+		rects []Rect // of the glyphs, in the order of the text
+		flipX bool
+	}{
+		{"not mirrored", Vec{X: 2, Y: 2}, nil, false},
+		// the whole text is mirrored: it goes to the left and every glyph is flipped
+		{"mirrored", Vec{X: -1, Y: 1}, []Rect{{X: -10, W: 10, H: 20}, {X: -20, W: 10, H: 20}},
+			true},
+		{"mirrored and zoomed", Vec{X: -2, Y: 1}, []Rect{{X: -20, W: 20, H: 20},
+			{X: -40, W: 20, H: 20}}, true},
+	}
+	for _, test := range tests {
+		out := &recorder{}
+		projection{IM.Scaled(Vec{}, test.zoom), out}.DrawText(Vec{}, picFont{}, "ab", Color{1, 1, 1, 1})
+		if test.rects == nil {
+			if !reflect.DeepEqual(out.texts, []string{"ab"}) || len(out.rects) != 0 {
+				t.Errorf("%s: got texts %q and %d glyphs, want the text at once", test.name,
+					out.texts, len(out.rects))
+			}
+			continue
+		}
+		if !reflect.DeepEqual(out.rects, test.rects) {
+			t.Errorf("%s: got glyphs at %v, want %v", test.name, out.rects, test.rects)
+		}
+		for i, opts := range out.options {
+			if opts.FlipX != test.flipX || opts.FlipY {
+				t.Errorf("%s: glyph %d: got flips %v %v, want %v false", test.name, i,
+					opts.FlipX, opts.FlipY, test.flipX)
+			}
+		}
+	}
+}
+
+func TestProjectionDrawPictureEx(t *testing.T) {
+	pic := &Picture{rect: sdl.Rect{W: 4, H: 2}, angle: 0.2}
+	rect := Rect{X: 10, Y: 10, W: 4, H: 2}
+	opts := PictureOptions{Pivot: Vec{X: 1, Y: 1}, Angle: 0.3, FlipX: true}
+
+	tests := []struct {
+		name string
+		zoom Vec
+		rect Rect
+		opts PictureOptions
+	}{
+		{"zoomed", Vec{X: 2, Y: 2}, Rect{X: 20, Y: 20, W: 8, H: 4},
+			PictureOptions{Pivot: Vec{X: 2, Y: 2}, Angle: 0.3, FlipX: true}},
+		// the pivot stays on the same point of the picture, which is now on the other side
+		{"mirrored horizontally", Vec{X: -1, Y: 1}, Rect{X: -14, Y: 10, W: 4, H: 2},
+			PictureOptions{Pivot: Vec{X: 3, Y: 1}, Angle: -0.7}},
+		{"mirrored vertically", Vec{X: 1, Y: -1}, Rect{X: 10, Y: -12, W: 4, H: 2},
+			PictureOptions{Pivot: Vec{X: 1, Y: 1}, Angle: -0.7, FlipX: true, FlipY: true}},
+		{"turned around", Vec{X: -1, Y: -1}, Rect{X: -14, Y: -12, W: 4, H: 2},
+			PictureOptions{Pivot: Vec{X: 3, Y: 1}, Angle: 0.3, FlipY: true}},
+	}
+	for _, test := range tests {
+		out := &recorder{}
+		projection{IM.Scaled(Vec{}, test.zoom), out}.DrawPictureEx(rect, pic, opts)
+		if len(out.rects) != 1 {
+			t.Errorf("%s: got %d pictures, want 1", test.name, len(out.rects))
+			continue
+		}
+		if got := out.rects[0]; got != test.rect {
+			t.Errorf("%s: got rect %v, want %v", test.name, got, test.rect)
+		}
+		got := out.options[0]
+		if !vecsClose(got.Pivot, test.opts.Pivot) || math.Abs(got.Angle-test.opts.Angle) > 1e-9 ||
+			got.FlipX != test.opts.FlipX || got.FlipY != test.opts.FlipY {
+			t.Errorf("%s: got options %+v, want %+v", test.name, got, test.opts)
+		}
+	}
+}
+
+func TestPictureMesh(t *testing.T) {
+	pic := &Picture{rect: sdl.Rect{X: 100, Y: 100, W: 4, H: 2}, angle: math.Pi / 4}
+	rect := Rect{W: 4, H: 2}
+
+	tests := []struct {
+		name      string
+		m         Matrix
+		opts      PictureOptions
+		positions []Vec
+		uvs       []Vec
+		color     Color
+	}{
+		{
+			"plain",
+			IM,
+			PictureOptions{Angle: -math.Pi / 4},
+			[]Vec{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 2}, {X: 0, Y: 2}},
+			[]Vec{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 2}, {X: 0, Y: 2}},
+			Color{1, 1, 1, 1},
+		},
+		{
+			"rotated around the pivot",
+			IM,
+			PictureOptions{Pivot: Vec{X: 4, Y: 2}, Angle: math.Pi / 4, Tint: Color{1, 0, 0, 1}},
+			[]Vec{{X: 6, Y: -2}, {X: 6, Y: 2}, {X: 4, Y: 2}, {X: 4, Y: -2}},
+			[]Vec{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 2}, {X: 0, Y: 2}},
+			Color{1, 0, 0, 1},
+		},
+		{
+			"flipped",
+			IM.Moved(Vec{X: 10, Y: 0}),
+			PictureOptions{Angle: -math.Pi / 4, FlipX: true, FlipY: true},
+			[]Vec{{X: 10, Y: 0}, {X: 14, Y: 0}, {X: 14, Y: 2}, {X: 10, Y: 2}},
+			[]Vec{{X: 4, Y: 2}, {X: 0, Y: 2}, {X: 0, Y: 0}, {X: 4, Y: 0}},
+			Color{1, 1, 1, 1},
+		},
+		{
+			"flipped horizontally",
+			IM,
+			PictureOptions{Angle: -math.Pi / 4, FlipX: true},
+			[]Vec{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 2}, {X: 0, Y: 2}},
+			[]Vec{{X: 4, Y: 0}, {X: 0, Y: 0}, {X: 0, Y: 2}, {X: 4, Y: 2}},
+			Color{1, 1, 1, 1},
+		},
+	}
+	for _, test := range tests {
+		mesh := projection{test.m, nil}.pictureMesh(rect, pic, test.opts)
+		if mesh.Picture != pic || len(mesh.Vertices) != 4 {
+			t.Errorf("%s: got %d vertices of %v, want 4 of the picture", test.name,
+				len(mesh.Vertices), mesh.Picture)
+			continue
+		}
+		for i, v := range mesh.Vertices {
+			if !vecsClose(v.Pos, test.positions[i]) || v.UV != test.uvs[i] || v.Color != test.color {
+				t.Errorf("%s: corner %d: got %v at %v with %v, want %v at %v with %v", test.name,
+					i, v.UV, v.Pos, v.Color, test.uvs[i], test.positions[i], test.color)
+			}
+		}
+	}
+}
+
+func TestProjectionDrawPictureExMirroredMesh(t *testing.T) {
+	// a rotated and mirrored camera draws a mesh, which the matrix mirrors as a whole, so the
+	// options are kept as they are
+	pic := &Picture{rect: sdl.Rect{W: 4, H: 2}, angle: 0.2}
+	rect := Rect{X: 10, Y: 10, W: 4, H: 2}
+	opts := PictureOptions{Pivot: Vec{X: 1, Y: 1}, Angle: 0.3}
+	m := IM.Scaled(Vec{}, Vec{X: -1, Y: 1}).Rotated(Vec{}, 0.5)
+
+	out := &recorder{}
+	projection{m, out}.DrawPictureEx(rect, pic, opts)
+	if len(out.meshes) != 1 {
+		t.Fatalf("got %d meshes, want 1", len(out.meshes))
+	}
+	pivot := rect.Pos().A(opts.Pivot)
+	for i, corner := range rectPoints(rect) {
+		want := m.Project(corner.S(pivot).Rotated(pic.angle + opts.Angle).A(pivot))
+		if got := out.meshes[0].Vertices[i].Pos; !vecsClose(got, want) {
+			t.Errorf("corner %d: got %v, want %v", i, got, want)
+		}
+		if got := out.meshes[0].Vertices[i].UV; got != corner.S(rect.Pos()) {
+			t.Errorf("corner %d: got UV %v, want it not flipped", i, got)
+		}
+	}
+}
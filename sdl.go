@@ -24,8 +24,8 @@ func makeWindow(cfg Config) (*sdl.Window, error) {
 		cfg.Title,
 		sdl.WINDOWPOS_UNDEFINED,
 		sdl.WINDOWPOS_UNDEFINED,
-		cfg.Width,
-		cfg.Height,
+		int32(cfg.Width),
+		int32(cfg.Height),
 		winFlags,
 	)
 	if err != nil {
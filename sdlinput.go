@@ -5,26 +5,42 @@ package gogame
 import "github.com/veandco/go-sdl2/sdl"
 
 type sdlInput struct {
-	window                                 *sdl.Window
-	windowX, windowY, windowW, windowH     int
-	windowMoved                            bool
-	windowResized                          bool
-	windowClosed                           bool
-	windowHasFocus                         bool
-	windowLostFocus                        bool
-	windowGainedFocus                      bool
-	prevMouseX, prevMouseY, mouseX, mouseY int
-	prevMouse, mouse                       map[int]bool
-	prevKeyboard, keyboard                 map[int]bool
+	window                             *sdl.Window
+	windowX, windowY, windowW, windowH int32
+	windowMoved                        bool
+	windowResized                      bool
+	windowClosed                       bool
+	windowHasFocus                     bool
+	windowLostFocus                    bool
+	windowGainedFocus                  bool
+	mouseX, mouseY                     int32
+	mouseDeltaX, mouseDeltaY           int
+	mouseWheelX, mouseWheelY           int
+	prevMouse, mouse                   map[int]bool
+	prevKeyboard, keyboard             map[int]bool
+	prevScancodes, scancodes           map[int]bool
+	keyMods                            int
+	mousePresses, mouseReleases        map[int]int
+	keyPresses, keyReleases            map[int]int
+	touches                            []Touch
+	events                             []Event
+	drops                              []Drop
 }
 
 func newSdlInput(window *sdl.Window) *sdlInput {
 	input := sdlInput{
-		window:       window,
-		prevMouse:    make(map[int]bool),
-		mouse:        make(map[int]bool),
-		prevKeyboard: make(map[int]bool),
-		keyboard:     make(map[int]bool),
+		window:        window,
+		prevMouse:     make(map[int]bool),
+		mouse:         make(map[int]bool),
+		prevKeyboard:  make(map[int]bool),
+		keyboard:      make(map[int]bool),
+		prevScancodes: make(map[int]bool),
+		scancodes:     make(map[int]bool),
+
+		mousePresses:  make(map[int]int),
+		mouseReleases: make(map[int]int),
+		keyPresses:    make(map[int]int),
+		keyReleases:   make(map[int]int),
 	}
 
 	input.windowX, input.windowY = window.GetPosition()
@@ -32,13 +48,12 @@ func newSdlInput(window *sdl.Window) *sdlInput {
 	input.windowHasFocus = window.GetFlags()&sdl.WINDOW_INPUT_FOCUS != 0
 	input.windowGainedFocus = input.windowHasFocus
 	input.mouseX, input.mouseY, _ = sdl.GetMouseState()
-	input.prevMouseX, input.prevMouseY = input.mouseX, input.mouseY
 
 	return &input
 }
 
-func (i *sdlInput) WindowPosition() (x, y int) { return i.windowX, i.windowY }
-func (i *sdlInput) WindowSize() (w, h int)     { return i.windowW, i.windowH }
+func (i *sdlInput) WindowPosition() (x, y int) { return int(i.windowX), int(i.windowY) }
+func (i *sdlInput) WindowSize() (w, h int)     { return int(i.windowW), int(i.windowH) }
 func (i *sdlInput) WindowMoved() bool          { return i.windowMoved }
 func (i *sdlInput) WindowResized() bool        { return i.windowResized }
 func (i *sdlInput) WindowClosed() bool         { return i.windowClosed }
@@ -48,18 +63,157 @@ func (i *sdlInput) WindowGainedFocus() bool    { return i.windowGainedFocus }
 
 func (i *sdlInput) MousePosition() Vec { return Vec{X: float64(i.mouseX), Y: float64(i.mouseY)} }
 func (i *sdlInput) MouseDelta() Vec {
-	return Vec{
-		X: float64(i.mouseX - i.prevMouseX),
-		Y: float64(i.mouseY - i.prevMouseY),
-	}
+	return Vec{X: float64(i.mouseDeltaX), Y: float64(i.mouseDeltaY)}
+}
+func (i *sdlInput) MouseWheel() Vec {
+	return Vec{X: float64(i.mouseWheelX), Y: float64(i.mouseWheelY)}
 }
 func (i *sdlInput) MouseDown(button int) bool     { return i.mouse[button] }
 func (i *sdlInput) MouseJustDown(button int) bool { return i.mouse[button] && !i.prevMouse[button] }
 func (i *sdlInput) MouseJustUp(button int) bool   { return !i.mouse[button] && i.prevMouse[button] }
+func (i *sdlInput) MousePresses(button int) int   { return i.mousePresses[button] }
+func (i *sdlInput) MouseReleases(button int) int  { return i.mouseReleases[button] }
 
 func (i *sdlInput) KeyDown(key int) bool     { return i.keyboard[key] }
 func (i *sdlInput) KeyJustDown(key int) bool { return i.keyboard[key] && !i.prevKeyboard[key] }
 func (i *sdlInput) KeyJustUp(key int) bool   { return !i.keyboard[key] && i.prevKeyboard[key] }
+func (i *sdlInput) KeyPresses(key int) int   { return i.keyPresses[key] }
+func (i *sdlInput) KeyReleases(key int) int  { return i.keyReleases[key] }
+
+func (i *sdlInput) ScancodeDown(scancode int) bool { return i.scancodes[scancode] }
+func (i *sdlInput) ScancodeJustDown(scancode int) bool {
+	return i.scancodes[scancode] && !i.prevScancodes[scancode]
+}
+func (i *sdlInput) ScancodeJustUp(scancode int) bool {
+	return !i.scancodes[scancode] && i.prevScancodes[scancode]
+}
+
+func (i *sdlInput) KeyMods() int         { return i.keyMods }
+func (i *sdlInput) ModDown(mod int) bool { return i.keyMods&mod != 0 }
+
+func (i *sdlInput) KeyFromScancode(scancode int) int {
+	return int(sdl.GetKeyFromScancode(sdl.Scancode(scancode)))
+}
+func (i *sdlInput) ScancodeFromKey(key int) int {
+	return int(sdl.GetScancodeFromKey(sdl.Keycode(key)))
+}
+func (i *sdlInput) KeyName(key int) string { return sdl.GetKeyName(sdl.Keycode(key)) }
+func (i *sdlInput) ScancodeName(scancode int) string {
+	return sdl.GetScancodeName(sdl.Scancode(scancode))
+}
+
+func (i *sdlInput) Events() []Event {
+	return append([]Event(nil), i.events...)
+}
+
+func (i *sdlInput) ClipboardText() string {
+	text, err := sdl.GetClipboardText()
+	if err != nil {
+		return ""
+	}
+	return text
+}
+
+func (i *sdlInput) ClipboardSetText(text string) {
+	sdl.SetClipboardText(text)
+}
+
+func (i *sdlInput) Drops() []Drop {
+	return append([]Drop(nil), i.drops...)
+}
+
+func (i *sdlInput) updateDrop(event *sdl.DropEvent) {
+	// SDL2 doesn't tell where the drop happened, the mouse is there though; the window
+	// usually doesn't have focus during a drop, so the global mouse state is needed
+	mouseX, mouseY, _ := sdl.GetGlobalMouseState()
+	windowX, windowY := i.window.GetPosition()
+	drop := Drop{Pos: Vec{X: float64(mouseX - windowX), Y: float64(mouseY - windowY)}}
+
+	switch event.Type {
+	case sdl.DROPFILE:
+		drop.Path = event.File
+	case sdl.DROPTEXT:
+		drop.Text = event.File
+	default:
+		return
+	}
+
+	i.drops = append(i.drops, drop)
+	i.events = append(i.events, Event{
+		Kind: EventDrop,
+		Time: eventTime(event.Timestamp),
+		Pos:  drop.Pos,
+		Drop: drop,
+	})
+}
+
+// eventTime converts an SDL timestamp (in milliseconds) to seconds.
+func eventTime(timestamp uint32) float64 {
+	return float64(timestamp) / 1000
+}
+
+func (i *sdlInput) Touches() []Touch {
+	return append([]Touch(nil), i.touches...)
+}
+
+func (i *sdlInput) touchIndex(id int64) int {
+	for index := range i.touches {
+		if i.touches[index].ID == id {
+			return index
+		}
+	}
+	return -1
+}
+
+func (i *sdlInput) updateTouch(event *sdl.TouchFingerEvent) {
+	// finger positions are normalized to (0, 1)
+	pos := Vec{
+		X: float64(event.X) * float64(i.windowW),
+		Y: float64(event.Y) * float64(i.windowH),
+	}
+	id := int64(event.FingerID)
+
+	if event.Type == sdl.FINGERDOWN {
+		i.touches = append(i.touches, Touch{
+			ID:        id,
+			Pos:       pos,
+			Pressure:  float64(event.Pressure),
+			JustBegan: true,
+		})
+		i.events = append(i.events, Event{
+			Kind:     EventTouchBegin,
+			Time:     eventTime(event.Timestamp),
+			Pos:      pos,
+			TouchID:  id,
+			Pressure: float64(event.Pressure),
+		})
+		return
+	}
+
+	index := i.touchIndex(id)
+	if index < 0 {
+		return
+	}
+	touch := &i.touches[index]
+	delta := pos.S(touch.Pos)
+	touch.Delta = touch.Delta.A(delta)
+	touch.Pos = pos
+	touch.Pressure = float64(event.Pressure)
+
+	kind := EventTouchMove
+	if event.Type == sdl.FINGERUP {
+		touch.JustEnded = true
+		kind = EventTouchEnd
+	}
+	i.events = append(i.events, Event{
+		Kind:     kind,
+		Time:     eventTime(event.Timestamp),
+		Pos:      pos,
+		Delta:    delta,
+		TouchID:  id,
+		Pressure: float64(event.Pressure),
+	})
+}
 
 func (i *sdlInput) update() {
 	i.windowMoved = false
@@ -68,6 +222,33 @@ func (i *sdlInput) update() {
 	i.windowHasFocus = i.window.GetFlags()&sdl.WINDOW_INPUT_FOCUS != 0
 	i.windowGainedFocus = false
 	i.windowLostFocus = false
+	i.mouseDeltaX, i.mouseDeltaY = 0, 0
+	i.mouseWheelX, i.mouseWheelY = 0, 0
+	i.events = i.events[:0]
+	i.drops = i.drops[:0]
+
+	for button := range i.mousePresses {
+		delete(i.mousePresses, button)
+	}
+	for button := range i.mouseReleases {
+		delete(i.mouseReleases, button)
+	}
+	for key := range i.keyPresses {
+		delete(i.keyPresses, key)
+	}
+	for key := range i.keyReleases {
+		delete(i.keyReleases, key)
+	}
+
+	touches := i.touches[:0]
+	for _, touch := range i.touches {
+		if !touch.JustEnded {
+			touch.JustBegan = false
+			touch.Delta = Vec{}
+			touches = append(touches, touch)
+		}
+	}
+	i.touches = touches
 
 	for button := range i.mouse {
 		i.prevMouse[button] = i.mouse[button]
@@ -75,40 +256,102 @@ func (i *sdlInput) update() {
 	for key := range i.keyboard {
 		i.prevKeyboard[key] = i.keyboard[key]
 	}
+	for scancode := range i.scancodes {
+		i.prevScancodes[scancode] = i.scancodes[scancode]
+	}
 
 	for event := sdl.PollEvent(); event != nil; event = sdl.PollEvent() {
-		switch event := event.(type) {
-		case *sdl.QuitEvent:
-			i.windowClosed = true
-		case *sdl.WindowEvent:
-			switch event.Event {
-			case sdl.WINDOWEVENT_MOVED:
-				i.windowMoved = true
-			case sdl.WINDOWEVENT_RESIZED, sdl.WINDOWEVENT_SIZE_CHANGED:
-				i.windowResized = true
-			case sdl.WINDOWEVENT_FOCUS_GAINED:
-				i.windowGainedFocus = true
-			case sdl.WINDOWEVENT_FOCUS_LOST:
-				i.windowLostFocus = true
-			case sdl.WINDOWEVENT_CLOSE:
-				i.windowClosed = true
-			}
-		case *sdl.MouseButtonEvent:
-			switch event.Type {
-			case sdl.MOUSEBUTTONDOWN:
-				i.mouse[int(event.Button)] = true
-			case sdl.MOUSEBUTTONUP:
-				i.mouse[int(event.Button)] = false
-			}
-		case *sdl.KeyDownEvent:
-			i.keyboard[int(event.Keysym.Sym)] = true
-		case *sdl.KeyUpEvent:
-			i.keyboard[int(event.Keysym.Sym)] = false
-		}
+		i.handleEvent(event)
 	}
 
 	i.windowX, i.windowY = i.window.GetPosition()
 	i.windowW, i.windowH = i.window.GetSize()
-	i.prevMouseX, i.prevMouseY = i.mouseX, i.mouseY
 	i.mouseX, i.mouseY, _ = sdl.GetMouseState()
+	i.keyMods = int(sdl.GetModState())
+}
+
+// handleEvent updates the state of the input by a single event.
+func (i *sdlInput) handleEvent(event sdl.Event) {
+	switch event := event.(type) {
+	case *sdl.QuitEvent:
+		i.windowClosed = true
+	case *sdl.WindowEvent:
+		switch event.Event {
+		case sdl.WINDOWEVENT_MOVED:
+			i.windowMoved = true
+		case sdl.WINDOWEVENT_RESIZED, sdl.WINDOWEVENT_SIZE_CHANGED:
+			i.windowResized = true
+		case sdl.WINDOWEVENT_FOCUS_GAINED:
+			i.windowGainedFocus = true
+		case sdl.WINDOWEVENT_FOCUS_LOST:
+			i.windowLostFocus = true
+		case sdl.WINDOWEVENT_CLOSE:
+			i.windowClosed = true
+		}
+	case *sdl.MouseMotionEvent:
+		// relative motion keeps coming in relative mouse mode, when the position is fixed
+		i.mouseDeltaX += int(event.XRel)
+		i.mouseDeltaY += int(event.YRel)
+		i.events = append(i.events, Event{
+			Kind:  EventMouseMove,
+			Time:  eventTime(event.Timestamp),
+			Pos:   Vec{X: float64(event.X), Y: float64(event.Y)},
+			Delta: Vec{X: float64(event.XRel), Y: float64(event.YRel)},
+		})
+	case *sdl.MouseWheelEvent:
+		x, y := int(event.X), int(event.Y)
+		if event.Direction == sdl.MOUSEWHEEL_FLIPPED {
+			x, y = -x, -y
+		}
+		i.mouseWheelX += x
+		i.mouseWheelY += y
+		i.events = append(i.events, Event{
+			Kind:  EventMouseWheel,
+			Time:  eventTime(event.Timestamp),
+			Delta: Vec{X: float64(x), Y: float64(y)},
+		})
+	case *sdl.MouseButtonEvent:
+		button := int(event.Button)
+		kind := EventMouseDown
+		switch event.Type {
+		case sdl.MOUSEBUTTONDOWN:
+			i.mouse[button] = true
+			i.mousePresses[button]++
+		case sdl.MOUSEBUTTONUP:
+			i.mouse[button] = false
+			i.mouseReleases[button]++
+			kind = EventMouseUp
+		}
+		i.events = append(i.events, Event{
+			Kind:   kind,
+			Time:   eventTime(event.Timestamp),
+			Button: button,
+			Pos:    Vec{X: float64(event.X), Y: float64(event.Y)},
+		})
+	case *sdl.TouchFingerEvent:
+		i.updateTouch(event)
+	case *sdl.DropEvent:
+		i.updateDrop(event)
+	case *sdl.KeyboardEvent:
+		key := int(event.Keysym.Sym)
+		down := event.Type == sdl.KEYDOWN
+		i.keyboard[key] = down
+		i.scancodes[int(event.Keysym.Scancode)] = down
+		kind := EventKeyDown
+		switch {
+		case down && event.Repeat == 0:
+			i.keyPresses[key]++
+		case !down:
+			i.keyReleases[key]++
+			kind = EventKeyUp
+		}
+		i.events = append(i.events, Event{
+			Kind:     kind,
+			Time:     eventTime(event.Timestamp),
+			Key:      key,
+			Scancode: int(event.Keysym.Scancode),
+			Mods:     int(event.Keysym.Mod),
+			Repeat:   event.Repeat != 0,
+		})
+	}
 }
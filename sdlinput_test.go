@@ -0,0 +1,152 @@
+package gogame
+
+import (
+	"reflect"
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestSDLInputHandleEvent(t *testing.T) {
+	keyDown := func(sym sdl.Keycode, scancode sdl.Scancode, mod uint16, repeat uint8) sdl.Event {
+		return &sdl.KeyboardEvent{
+			Type:      sdl.KEYDOWN,
+			Timestamp: 1000,
+			Repeat:    repeat,
+			Keysym:    sdl.Keysym{Scancode: scancode, Sym: sym, Mod: mod},
+		}
+	}
+	keyUp := func(sym sdl.Keycode, scancode sdl.Scancode) sdl.Event {
+		return &sdl.KeyboardEvent{
+			Type:      sdl.KEYUP,
+			Timestamp: 2000,
+			Keysym:    sdl.Keysym{Scancode: scancode, Sym: sym},
+		}
+	}
+	mouseButton := func(typ uint32, x, y int32) sdl.Event {
+		return &sdl.MouseButtonEvent{Type: typ, Timestamp: 500, Button: sdl.BUTTON_LEFT, X: x, Y: y}
+	}
+
+	tests := []struct {
+		name   string
+		events []sdl.Event
+		want   []Event
+		check  func(i *sdlInput) bool
+		state  string // what check expects
+	}{
+		{
+			"wheel",
+			[]sdl.Event{&sdl.MouseWheelEvent{Type: sdl.MOUSEWHEEL, Timestamp: 1000, X: 1, Y: 2}},
+			[]Event{{Kind: EventMouseWheel, Time: 1, Delta: Vec{X: 1, Y: 2}}},
+			func(i *sdlInput) bool { return i.MouseWheel() == Vec{X: 1, Y: 2} },
+			"wheel (1, 2)",
+		},
+		{
+			"flipped wheel",
+			[]sdl.Event{
+				&sdl.MouseWheelEvent{Type: sdl.MOUSEWHEEL, X: 1, Y: 2,
+					Direction: sdl.MOUSEWHEEL_FLIPPED},
+				&sdl.MouseWheelEvent{Type: sdl.MOUSEWHEEL, Y: 1, Direction: sdl.MOUSEWHEEL_FLIPPED},
+			},
+			[]Event{
+				{Kind: EventMouseWheel, Delta: Vec{X: -1, Y: -2}},
+				{Kind: EventMouseWheel, Delta: Vec{X: 0, Y: -1}},
+			},
+			func(i *sdlInput) bool { return i.MouseWheel() == Vec{X: -1, Y: -3} },
+			"wheel (-1, -3)",
+		},
+		{
+			"click within a frame",
+			[]sdl.Event{
+				mouseButton(sdl.MOUSEBUTTONDOWN, 10, 20),
+				mouseButton(sdl.MOUSEBUTTONUP, 11, 20),
+				mouseButton(sdl.MOUSEBUTTONDOWN, 11, 20),
+			},
+			[]Event{
+				{Kind: EventMouseDown, Time: 0.5, Button: MouseButtonLeft, Pos: Vec{X: 10, Y: 20}},
+				{Kind: EventMouseUp, Time: 0.5, Button: MouseButtonLeft, Pos: Vec{X: 11, Y: 20}},
+				{Kind: EventMouseDown, Time: 0.5, Button: MouseButtonLeft, Pos: Vec{X: 11, Y: 20}},
+			},
+			func(i *sdlInput) bool {
+				return i.MousePresses(MouseButtonLeft) == 2 &&
+					i.MouseReleases(MouseButtonLeft) == 1 && i.MouseDown(MouseButtonLeft)
+			},
+			"2 presses, 1 release, down",
+		},
+		{
+			"key repeat",
+			[]sdl.Event{
+				keyDown(sdl.K_a, sdl.SCANCODE_Q, sdl.KMOD_LSHIFT, 0),
+				keyDown(sdl.K_a, sdl.SCANCODE_Q, sdl.KMOD_LSHIFT, 1),
+			},
+			[]Event{
+				{Kind: EventKeyDown, Time: 1, Key: KeyA, Scancode: ScancodeQ, Mods: ModLShift},
+				{Kind: EventKeyDown, Time: 1, Key: KeyA, Scancode: ScancodeQ, Mods: ModLShift,
+					Repeat: true},
+			},
+			func(i *sdlInput) bool {
+				return i.KeyPresses(KeyA) == 1 && i.KeyDown(KeyA) && i.ScancodeDown(ScancodeQ) &&
+					!i.ScancodeDown(ScancodeA)
+			},
+			"1 press, down by key and scancode",
+		},
+		{
+			"key released",
+			[]sdl.Event{
+				keyDown(sdl.K_a, sdl.SCANCODE_Q, 0, 0),
+				keyUp(sdl.K_a, sdl.SCANCODE_Q),
+				keyDown(sdl.K_a, sdl.SCANCODE_Q, 0, 0),
+				keyUp(sdl.K_a, sdl.SCANCODE_Q),
+			},
+			[]Event{
+				{Kind: EventKeyDown, Time: 1, Key: KeyA, Scancode: ScancodeQ},
+				{Kind: EventKeyUp, Time: 2, Key: KeyA, Scancode: ScancodeQ},
+				{Kind: EventKeyDown, Time: 1, Key: KeyA, Scancode: ScancodeQ},
+				{Kind: EventKeyUp, Time: 2, Key: KeyA, Scancode: ScancodeQ},
+			},
+			func(i *sdlInput) bool {
+				return i.KeyPresses(KeyA) == 2 && i.KeyReleases(KeyA) == 2 && !i.KeyDown(KeyA) &&
+					!i.ScancodeDown(ScancodeQ)
+			},
+			"2 presses, 2 releases, up",
+		},
+		{
+			"drops",
+			[]sdl.Event{
+				&sdl.DropEvent{Type: sdl.DROPBEGIN},
+				&sdl.DropEvent{Type: sdl.DROPFILE, Timestamp: 3000, File: "/tmp/a.png"},
+				&sdl.DropEvent{Type: sdl.DROPTEXT, Timestamp: 3000, File: "hello"},
+				&sdl.DropEvent{Type: sdl.DROPCOMPLETE},
+			},
+			[]Event{
+				{Kind: EventDrop, Time: 3, Drop: Drop{Path: "/tmp/a.png"}},
+				{Kind: EventDrop, Time: 3, Drop: Drop{Text: "hello"}},
+			},
+			func(i *sdlInput) bool {
+				return len(i.drops) == 2 && i.drops[0].Path == "/tmp/a.png" &&
+					i.drops[1].Text == "hello"
+			},
+			"a file and a text dropped",
+		},
+	}
+	for _, test := range tests {
+		i := newSdlInput(nil)
+		for _, event := range test.events {
+			i.handleEvent(event)
+		}
+
+		got := i.Events()
+		for k := range got {
+			// drops are where the mouse is, which has nothing to do with the events
+			if got[k].Kind == EventDrop {
+				got[k].Pos, got[k].Drop.Pos = Vec{}, Vec{}
+			}
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%s: got events %+v, want %+v", test.name, got, test.want)
+		}
+		if !test.check(i) {
+			t.Errorf("%s: got the wrong state, want %s", test.name, test.state)
+		}
+	}
+}
@@ -5,12 +5,14 @@ package gogame
 import (
 	"math"
 
+	"github.com/pkg/errors"
 	"github.com/veandco/go-sdl2/sdl"
-	gfx "github.com/veandco/go-sdl2/sdl_gfx"
 )
 
 type sdlOutput struct {
-	window *sdl.Window
+	window        *sdl.Window
+	cursor        *sdl.Cursor
+	cursorSurface *sdl.Surface
 	rendererOutput
 }
 
@@ -18,9 +20,10 @@ func newSdlOutput(window *sdl.Window, renderer *sdl.Renderer) *sdlOutput {
 	return &sdlOutput{
 		window: window,
 		rendererOutput: rendererOutput{
-			renderer: renderer,
-			textures: make(map[*sdl.Surface]*sdl.Texture),
-			mask:     Color{1, 1, 1, 1},
+			renderer:  renderer,
+			cache:     newTextureCache(),
+			mask:      Color{1, 1, 1, 1},
+			blendMode: BlendAlpha,
 		},
 	}
 }
@@ -38,131 +41,837 @@ func (o *sdlOutput) WindowSetFullscreen(fullscreen bool) {
 }
 
 func (o *sdlOutput) WindowResize(w, h int) {
-	o.window.SetSize(w, h)
+	o.window.SetSize(int32(w), int32(h))
+}
+
+func (o *sdlOutput) CursorSetVisible(visible bool) {
+	if visible {
+		sdl.ShowCursor(sdl.ENABLE)
+	} else {
+		sdl.ShowCursor(sdl.DISABLE)
+	}
+}
+
+func (o *sdlOutput) CursorSetGrabbed(grabbed bool) {
+	o.window.SetGrab(grabbed)
+}
+
+func (o *sdlOutput) CursorSetRelative(relative bool) {
+	sdl.SetRelativeMouseMode(relative)
+}
+
+func (o *sdlOutput) CursorSetPicture(pic *Picture, hotX, hotY int) {
+	if pic == nil {
+		sdl.SetCursor(sdl.GetDefaultCursor())
+		o.freeCursor()
+		return
+	}
+
+	surface, err := pic.cutSurface()
+	if err != nil {
+		panic(errors.Wrap(err, "failed to create a cursor"))
+	}
+	cursor := sdl.CreateColorCursor(surface, int32(hotX), int32(hotY))
+	if cursor == nil {
+		surface.Free()
+		panic("failed to create a cursor")
+	}
+	sdl.SetCursor(cursor)
+
+	// the old cursor can only be freed once it's not in use
+	o.freeCursor()
+	o.cursor, o.cursorSurface = cursor, surface
+}
+
+func (o *sdlOutput) freeCursor() {
+	if o.cursor != nil {
+		sdl.FreeCursor(o.cursor)
+		o.cursorSurface.Free()
+		o.cursor, o.cursorSurface = nil, nil
+	}
 }
 
 func (o *sdlOutput) OutputRect() Rect {
+	if rect, ok := o.viewportRect(); ok {
+		return rect
+	}
 	w, h := o.window.GetSize()
 	return Rect{X: 0, Y: 0, W: float64(w), H: float64(h)}
 }
 
-// rendererOutput implements all VideoOutput methods for an SDL renderer except for OutputRect.
+// rendererOutput implements all VideoOutput methods for an SDL renderer except for OutputRect,
+// and TargetCanvasOutput. Several outputs can share a renderer, each drawing to its own target.
 type rendererOutput struct {
 	renderer *sdl.Renderer
-	textures map[*sdl.Surface]*sdl.Texture
+	target   *sdl.Texture  // nil for the default target of the renderer
+	cache    *textureCache // shared by all outputs of the renderer
 	mask     Color
+
+	// surface holds the pixels of canvases: Canvas draws right into it, TargetCanvas reads its
+	// target back into it
+	surface *sdl.Surface
+
+	// premultiplied is set for canvases, which store colors premultiplied by alpha, as drawing
+	// with BlendAlpha onto a transparent target produces them
+	premultiplied bool
+
+	blendMode BlendMode
+	antialias bool
+	clips     []clipState
+
+	// dirty is set by every use of the renderer, so that target canvases know when the pixels
+	// read back last are out of date
+	dirty bool
+
+	// reused by DrawBatch to avoid allocating every frame
+	vertices []sdl.Vertex
+	indices  []int32
+}
+
+// boundOutputs are the outputs each renderer was bound to last.
+var boundOutputs = make(map[*sdl.Renderer]*rendererOutput)
+
+// bind makes the renderer draw to the target of the output, with the output's blend mode and
+// clip. It needs to be called before using the renderer, because it may be shared.
+func (o *rendererOutput) bind() {
+	o.dirty = true
+	if o.surface != nil && o.target == nil {
+		surfaceVersions[o.surface]++ // drawing right into the surface
+	}
+	if boundOutputs[o.renderer] == o {
+		return
+	}
+	boundOutputs[o.renderer] = o
+	o.renderer.SetRenderTarget(o.target)
+	o.renderer.SetDrawBlendMode(sdl.BlendMode(o.blendMode))
+	o.applyClip()
 }
 
 func (o *rendererOutput) SetMask(color Color) {
 	o.mask = color
 }
 
+func (o *rendererOutput) SetBlendMode(mode BlendMode) {
+	o.bind()
+	if o.renderer.SetDrawBlendMode(sdl.BlendMode(mode)) != nil {
+		mode = BlendAlpha // not supported by the renderer
+		o.renderer.SetDrawBlendMode(sdl.BlendMode(mode))
+	}
+	o.blendMode = mode
+}
+
 func (o *rendererOutput) Clear(color Color) {
+	o.bind()
 	color = color.Mul(o.mask)
+	if o.premultiplied {
+		color = color.premultiplied()
+	}
 	o.renderer.SetDrawColor(color.toSDLRGBA())
-	o.renderer.Clear()
+	if len(o.clips) == 0 {
+		o.renderer.Clear()
+		return
+	}
+
+	// SDL clears the whole target regardless of the clip, so the clip is filled instead
+	top := o.clips[len(o.clips)-1]
+	clip := top.clip
+	clip.X, clip.Y = clip.X-top.viewport.X, clip.Y-top.viewport.Y
+	o.renderer.SetDrawBlendMode(sdl.BLENDMODE_NONE)
+	o.renderer.FillRect(&clip)
+	o.renderer.SetDrawBlendMode(sdl.BlendMode(o.blendMode))
+}
+
+// clipState is an entry of the clip stack. Both rectangles are in the coordinates of the
+// whole target.
+type clipState struct {
+	viewport, clip sdl.Rect
+	isViewport     bool
+}
+
+func (o *rendererOutput) PushClip(rect Rect) {
+	o.pushClip(rect, false)
+}
+
+func (o *rendererOutput) PopClip() {
+	o.popClip(false)
+}
+
+func (o *rendererOutput) PushViewport(rect Rect) {
+	o.pushClip(rect, true)
+}
+
+func (o *rendererOutput) PopViewport() {
+	o.popClip(true)
+}
+
+func (o *rendererOutput) pushClip(rect Rect, isViewport bool) {
+	o.bind()
+	var top clipState
+	if len(o.clips) > 0 {
+		top = o.clips[len(o.clips)-1]
+	} else {
+		w, h, _ := o.renderer.GetOutputSize()
+		top.viewport = sdl.Rect{X: 0, Y: 0, W: w, H: h}
+		top.clip = top.viewport
+	}
+
+	x1, y1 := math.Round(rect.X), math.Round(rect.Y)
+	x2, y2 := math.Round(rect.X+rect.W), math.Round(rect.Y+rect.H)
+	x1, x2 = math.Min(x1, x2), math.Max(x1, x2)
+	y1, y2 = math.Min(y1, y2), math.Max(y1, y2)
+	pixels := sdl.Rect{
+		X: int32(x1) + top.viewport.X,
+		Y: int32(y1) + top.viewport.Y,
+		W: int32(x2 - x1),
+		H: int32(y2 - y1),
+	}
+
+	state := clipState{viewport: top.viewport, isViewport: isViewport}
+	if isViewport {
+		state.viewport = pixels
+	}
+	var ok bool
+	if state.clip, ok = pixels.Intersect(&top.clip); !ok {
+		// SDL treats an empty clip as no clip, so nothing is visible through one pixel off
+		// the target instead
+		state.clip = sdl.Rect{X: -1, Y: -1, W: 1, H: 1}
+	}
+	o.clips = append(o.clips, state)
+	o.applyClip()
+}
+
+func (o *rendererOutput) popClip(isViewport bool) {
+	o.bind()
+	if len(o.clips) == 0 {
+		panic("nothing to pop, no clip or viewport pushed")
+	}
+	if o.clips[len(o.clips)-1].isViewport != isViewport {
+		panic("clips and viewports must be popped in reverse order of pushing")
+	}
+	o.clips = o.clips[:len(o.clips)-1]
+	o.applyClip()
+}
+
+// applyClip sets the renderer's viewport and clip according to the top of the clip stack.
+func (o *rendererOutput) applyClip() {
+	if len(o.clips) == 0 {
+		o.renderer.SetViewport(nil)
+		o.renderer.SetClipRect(nil)
+		return
+	}
+	top := o.clips[len(o.clips)-1]
+	o.renderer.SetViewport(&top.viewport)
+	// the clip is relative to the viewport
+	clip := top.clip
+	clip.X, clip.Y = clip.X-top.viewport.X, clip.Y-top.viewport.Y
+	o.renderer.SetClipRect(&clip)
+}
+
+// viewportRect returns the rectangle of the current viewport at (0, 0) in pixels of the target,
+// if there's one. Plain clips don't count, they don't change the output rectangle.
+func (o *rendererOutput) viewportRect() (Rect, bool) {
+	for i := len(o.clips) - 1; i >= 0; i-- {
+		if o.clips[i].isViewport {
+			viewport := o.clips[i].viewport
+			return Rect{X: 0, Y: 0, W: float64(viewport.W), H: float64(viewport.H)}, true
+		}
+	}
+	return Rect{}, false
+}
+
+func (o *rendererOutput) SetAntialias(antialias bool) {
+	o.antialias = antialias
 }
 
 func (o *rendererOutput) DrawPoint(point Vec, color Color) {
+	o.bind()
 	color = color.Mul(o.mask)
-	gfx.PixelColor(o.renderer, int(point.X+0.5), int(point.Y+0.5), color.toSDL())
+	o.renderer.SetDrawColor(color.toSDLRGBA())
+	o.renderer.DrawPointF(float32(point.X), float32(point.Y))
 }
 
 func (o *rendererOutput) DrawLine(a, b Vec, thickness float64, color Color) {
+	o.bind()
 	color = color.Mul(o.mask)
-	gfx.ThickLineColor(
-		o.renderer,
-		int(a.X+0.5),
-		int(a.Y+0.5),
-		int(b.X+0.5),
-		int(b.Y+0.5),
-		int(thickness+0.5),
-		color.toSDL(),
-	)
+	o.drawPolyline([]Vec{a, b}, false, thickStroke(thickness), color)
 }
 
 func (o *rendererOutput) DrawPolygon(points []Vec, thickness float64, color Color) {
+	o.bind()
 	color = color.Mul(o.mask)
-	if thickness == 0 {
-		xInt16 := make([]int16, len(points))
-		yInt16 := make([]int16, len(points))
-		for i := 0; i < len(points); i++ {
-			xInt16[i] = int16(points[i].X + 0.5)
-			yInt16[i] = int16(points[i].Y + 0.5)
+	if thickness != 0 {
+		o.drawPolyline(points, true, thickStroke(thickness), color)
+		return
+	}
+	o.fillPolygon(points, color)
+	o.drawFringes([][]Vec{positive(points)}, color)
+}
+
+func (o *rendererOutput) DrawPolyline(points []Vec, closed bool, stroke Stroke, color Color) {
+	o.bind()
+	o.drawPolyline(points, closed, stroke, color.Mul(o.mask))
+}
+
+func (o *rendererOutput) DrawPath(path *Path, color Color) {
+	o.bind()
+	color = color.Mul(o.mask)
+	cache := path.triangulated()
+	o.fillTriangles(cache.triangles, color)
+	o.drawFringes(cache.outlines, color)
+}
+
+func (o *rendererOutput) DrawRect(rect Rect, thickness float64, color Color) {
+	o.bind()
+	color = color.Mul(o.mask)
+	if !o.antialias && (thickness == 0 || thickness == 1) {
+		r := sdl.FRect{
+			X: float32(rect.X),
+			Y: float32(rect.Y),
+			W: float32(rect.W),
+			H: float32(rect.H),
 		}
-		gfx.FilledPolygonColor(o.renderer, xInt16, yInt16, color.toSDL())
-	} else {
-		if len(points) == 1 {
-			x, y := int(points[0].X+0.5), int(points[0].Y+0.5)
-			gfx.FilledCircleColor(o.renderer, x, y, int(thickness/2+0.5), color.toSDL())
+		o.renderer.SetDrawColor(color.toSDLRGBA())
+		if thickness == 0 {
+			o.renderer.FillRectF(&r)
 		} else {
-			for i := 0; i < len(points); i++ {
-				j := (i + 1) % len(points)
-				x1, y1 := int(points[i].X+0.5), int(points[i].Y+0.5)
-				x2, y2 := int(points[j].X+0.5), int(points[j].Y+0.5)
-				gfx.ThickLineColor(o.renderer, x1, y1, x2, y2, int(thickness+0.5), color.toSDL())
-				gfx.FilledCircleColor(o.renderer, x1, y1, int(thickness/2+0.5), color.toSDL())
+			o.renderer.DrawRectF(&r)
+		}
+		return
+	}
+
+	points := rectPoints(rect)
+	if thickness == 0 {
+		o.fillPolygon(points, color)
+		o.drawFringes([][]Vec{positive(points)}, color)
+		return
+	}
+	o.drawPolyline(points, true, thickStroke(thickness), color)
+}
+
+func (o *rendererOutput) DrawCircle(center Vec, radius, thickness float64, color Color) {
+	o.DrawEllipse(center, Vec{X: radius, Y: radius}, thickness, color)
+}
+
+func (o *rendererOutput) DrawEllipse(center, radius Vec, thickness float64, color Color) {
+	o.DrawPolygon(ellipsePoints(center, radius), thickness, color)
+}
+
+func (o *rendererOutput) DrawArc(center, radius Vec, start, end, thickness float64, color Color) {
+	o.bind()
+	points := arcPoints(center, radius, start, end)
+	o.drawPolyline(points, false, thickStroke(thickness), color.Mul(o.mask))
+}
+
+func (o *rendererOutput) DrawPie(center, radius Vec, start, end, thickness float64, color Color) {
+	points := append([]Vec{center}, arcPoints(center, radius, start, end)...)
+	o.DrawPolygon(points, thickness, color)
+}
+
+func (o *rendererOutput) DrawRoundedRect(rect Rect, radius, thickness float64, color Color) {
+	o.DrawPolygon(roundedRectPoints(rect, radius), thickness, color)
+}
+
+func (o *rendererOutput) DrawBezier(points []Vec, thickness float64, color Color) {
+	o.bind()
+	points = bezierPoints(points)
+	o.drawPolyline(points, false, thickStroke(thickness), color.Mul(o.mask))
+}
+
+func (o *rendererOutput) DrawGradientPolygon(points []Vec, gradient Gradient) {
+	o.DrawMesh(gradientMesh(points, gradient))
+}
+
+func (o *rendererOutput) DrawGradientRect(rect Rect, gradient Gradient) {
+	o.DrawGradientPolygon(rectPoints(rect), gradient)
+}
+
+func (o *rendererOutput) DrawMesh(mesh *Mesh) {
+	o.bind()
+	var (
+		texture       *sdl.Texture
+		premultiplied bool
+		offset        Vec
+		size          = Vec{X: 1, Y: 1}
+	)
+	if mesh.Picture != nil {
+		// colors go into the vertices, the texture must not modulate them again
+		texture, premultiplied = o.pictureTexture(mesh.Picture)
+		o.setTextureBlendMode(texture, premultiplied)
+		texture.SetColorMod(255, 255, 255)
+		texture.SetAlphaMod(255)
+		offset = Vec{X: float64(mesh.Picture.rect.X), Y: float64(mesh.Picture.rect.Y)}
+		size = Vec{X: float64(mesh.Picture.surface.W), Y: float64(mesh.Picture.surface.H)}
+	}
+
+	o.vertices, o.indices = o.vertices[:0], o.indices[:0]
+	for _, v := range mesh.Vertices {
+		color := v.Color.Mul(o.mask)
+		if premultiplied {
+			color = color.premultiplied()
+		}
+		uv := v.UV.A(offset)
+		o.vertices = append(o.vertices, sdl.Vertex{
+			Position: sdl.FPoint{X: float32(v.Pos.X), Y: float32(v.Pos.Y)},
+			Color:    color.toSDL(),
+			TexCoord: sdl.FPoint{X: float32(uv.X / size.X), Y: float32(uv.Y / size.Y)},
+		})
+	}
+	if mesh.Indices == nil {
+		for i := 0; i < len(mesh.Vertices)/3*3; i++ {
+			o.indices = append(o.indices, int32(i))
+		}
+	}
+	for _, i := range mesh.Indices {
+		o.indices = append(o.indices, int32(i))
+	}
+	o.renderGeometry(texture)
+}
+
+// renderGeometry draws the triangles collected in vertices and indices. SDL can't be called
+// with no triangles.
+func (o *rendererOutput) renderGeometry(texture *sdl.Texture) {
+	if len(o.indices) > 0 {
+		o.renderer.RenderGeometry(texture, o.vertices, o.indices)
+	}
+}
+
+// drawPolyline draws lines connecting the points with a stroke. If closed is true, the last
+// point is connected to the first one. The color must be masked already.
+func (o *rendererOutput) drawPolyline(points []Vec, closed bool, stroke Stroke, color Color) {
+	polylines, closed := strokePolylines(points, closed, stroke)
+
+	if stroke.Thickness <= 1 {
+		for _, points := range polylines {
+			if o.antialias {
+				o.drawThinLines(points, closed, math.Max(stroke.Thickness, 0.5), color)
+				continue
+			}
+			segments := len(points) - 1
+			if closed {
+				segments = len(points)
+			}
+			o.renderer.SetDrawColor(color.toSDLRGBA())
+			for i := 0; i < segments; i++ {
+				a, b := points[i], points[(i+1)%len(points)]
+				o.renderer.DrawLineF(float32(a.X), float32(a.Y), float32(b.X), float32(b.Y))
 			}
 		}
+		return
 	}
+
+	o.vertices, o.indices = o.vertices[:0], o.indices[:0]
+	for _, points := range polylines {
+		strip, flatEnds := strokeStrip(points, closed, stroke)
+		o.addStrip(strip, flatEnds, color)
+	}
+	o.renderGeometry(nil)
 }
 
-func (o *rendererOutput) DrawRect(rect Rect, thickness float64, color Color) {
-	color = color.Mul(o.mask)
-	if thickness == 0 {
-		gfx.BoxColor(
-			o.renderer,
-			int(rect.X+0.5),
-			int(rect.Y+0.5),
-			int(rect.X+rect.W+0.5),
-			int(rect.Y+rect.H+0.5),
-			color.toSDL(),
+// addStrip adds the triangles of a stroke strip to vertices and indices. If the output is
+// anti-aliased, the edges of the strip get fringes like the ones of drawFringes.
+func (o *rendererOutput) addStrip(strip []strokeSection, flatEnds bool, color Color) {
+	if len(strip) < 2 {
+		return
+	}
+	fill, edge, outer := color.toSDL(), color.toSDL(), color.toSDL()
+	edge.A /= 2
+	outer.A = 0
+
+	quad := func(a, b, c, d sdl.Vertex) {
+		base := int32(len(o.vertices))
+		o.vertices = append(o.vertices, a, b, c, d)
+		o.indices = append(o.indices, base, base+1, base+2, base, base+2, base+3)
+	}
+	for i := 1; i < len(strip); i++ {
+		s, t := strip[i-1], strip[i]
+		quad(colorVertex(s.left, fill), colorVertex(t.left, fill),
+			colorVertex(t.right, fill), colorVertex(s.right, fill))
+		if !o.antialias {
+			continue
+		}
+		quad(colorVertex(s.left, edge), colorVertex(t.left, edge),
+			colorVertex(t.left.A(t.leftOut), outer), colorVertex(s.left.A(s.leftOut), outer))
+		quad(colorVertex(s.right, edge), colorVertex(t.right, edge),
+			colorVertex(t.right.A(t.rightOut), outer), colorVertex(s.right.A(s.rightOut), outer))
+	}
+
+	if !o.antialias || !flatEnds {
+		return
+	}
+	// fringes across the flat ends, with the corners between them and the side fringes
+	for i, end := range []strokeSection{strip[0], strip[len(strip)-1]} {
+		across := end.left.S(end.right)
+		if across.Len() == 0 {
+			continue
+		}
+		out := Vec{X: across.Y, Y: -across.X}.D(across.Len()) // the way the line goes
+		if i == 0 {
+			out = out.M(-1)
+		}
+		quad(colorVertex(end.left, edge), colorVertex(end.right, edge),
+			colorVertex(end.right.A(out), outer), colorVertex(end.left.A(out), outer))
+		quad(colorVertex(end.left, edge), colorVertex(end.left.A(end.leftOut), outer),
+			colorVertex(end.left.A(end.leftOut).A(out), outer), colorVertex(end.left.A(out), outer))
+		quad(colorVertex(end.right, edge), colorVertex(end.right.A(end.rightOut), outer),
+			colorVertex(end.right.A(end.rightOut).A(out), outer), colorVertex(end.right.A(out), outer))
+	}
+}
+
+// fillPolygon fills a simple polygon.
+func (o *rendererOutput) fillPolygon(points []Vec, color Color) {
+	o.vertices, o.indices = o.vertices[:0], o.indices[:0]
+	for _, p := range points {
+		o.vertices = append(o.vertices, colorVertex(p, color.toSDL()))
+	}
+	for _, i := range triangulate(points) {
+		o.indices = append(o.indices, int32(i))
+	}
+	o.renderGeometry(nil)
+}
+
+// fillTriangles fills triangles, three points per triangle.
+func (o *rendererOutput) fillTriangles(triangles []Vec, color Color) {
+	o.vertices, o.indices = o.vertices[:0], o.indices[:0]
+	for i, p := range triangles {
+		o.vertices = append(o.vertices, colorVertex(p, color.toSDL()))
+		o.indices = append(o.indices, int32(i))
+	}
+	o.renderGeometry(nil)
+}
+
+// drawFringes smooths the edges of filled polygons if the output is anti-aliased. A one pixel
+// wide band fading from half of the color's alpha to transparent is drawn just outside of
+// every edge. The outlines of the polygons must go so that the filled side of each edge is
+// the same as for a polygon with positive area (see positive).
+func (o *rendererOutput) drawFringes(outlines [][]Vec, color Color) {
+	if !o.antialias {
+		return
+	}
+
+	inner, outer := color.toSDL(), color.toSDL()
+	inner.A /= 2
+	outer.A = 0
+
+	o.vertices, o.indices = o.vertices[:0], o.indices[:0]
+	for _, points := range outlines {
+		n := len(points)
+		normals, lengths := make([]Vec, n), make([]float64, n)
+		for i := range points {
+			dir := points[(i+1)%n].S(points[i])
+			lengths[i] = dir.Len()
+			if lengths[i] > 0 {
+				normals[i] = Vec{X: dir.Y, Y: -dir.X}.D(lengths[i])
+			}
+		}
+
+		// where the bands of two edges go out from a corner, at a concave corner they meet
+		// instead of overlapping, unless the edges are too short for that
+		starts, ends := make([]Vec, n), make([]Vec, n)
+		for i := range points {
+			prev := normals[(i+n-1)%n]
+			starts[i], ends[i] = normals[i], prev
+			if cross(prev, normals[i]) >= 0 || 1+dot(prev, normals[i]) < 1e-9 {
+				continue
+			}
+			miter := prev.A(normals[i]).D(1 + dot(prev, normals[i]))
+			if math.Abs(cross(miter, prev)) <= lengths[(i+n-1)%n] &&
+				math.Abs(cross(miter, normals[i])) <= lengths[i] {
+				starts[i], ends[i] = miter, miter
+			}
+		}
+
+		for i := range points {
+			a, b := points[i], points[(i+1)%n]
+			base := int32(len(o.vertices))
+			o.vertices = append(o.vertices,
+				colorVertex(a, inner),
+				colorVertex(b, inner),
+				colorVertex(a.A(starts[i]), outer),
+				colorVertex(b.A(ends[(i+1)%n]), outer),
+			)
+			o.indices = append(o.indices, base, base+1, base+3, base, base+3, base+2)
+
+			// fill the gap between the bands of two edges at a convex corner
+			prev := normals[(i+n-1)%n]
+			if cross(prev, normals[i]) > 0 {
+				base := int32(len(o.vertices))
+				o.vertices = append(o.vertices,
+					colorVertex(a, inner),
+					colorVertex(a.A(prev), outer),
+					colorVertex(a.A(normals[i]), outer),
+				)
+				o.indices = append(o.indices, base, base+1, base+2)
+			}
+		}
+	}
+	o.renderGeometry(nil)
+}
+
+// drawThinLines draws anti-aliased lines at most one pixel thick. The lines are a ridge fading
+// from the color (scaled by thickness) in the middle to transparent one pixel to the sides.
+// Consecutive lines share the ridge at the point between them, so they don't overlap there.
+func (o *rendererOutput) drawThinLines(points []Vec, closed bool, thickness float64, color Color) {
+	middle, side := color.toSDL(), color.toSDL()
+	middle.A = byte(float64(middle.A) * thickness)
+	side.A = 0
+
+	points = uniquePoints(points, closed)
+	if len(points) < 2 {
+		return
+	}
+	segments := len(points) - 1
+	if closed {
+		segments = len(points)
+	}
+	normals := make([]Vec, segments)
+	for i := range normals {
+		dir := points[(i+1)%len(points)].S(points[i])
+		normals[i] = Vec{X: -dir.Y, Y: dir.X}.D(dir.Len())
+	}
+
+	o.vertices, o.indices = o.vertices[:0], o.indices[:0]
+	for i, p := range points {
+		offset := normals[i%segments]
+		if i == segments { // the end of an open polyline
+			offset = normals[segments-1]
+		}
+		if closed || (i > 0 && i < len(points)-1) {
+			// the sides stay parallel to both lines, but go at most two pixels away
+			prev, next := normals[(i+segments-1)%segments], normals[i%segments]
+			if miter := prev.A(next); miter.Len() > 1e-9 {
+				offset = miter.M(math.Min(1/(1+dot(prev, next)), 2/miter.Len()))
+			}
+		}
+		o.vertices = append(o.vertices,
+			colorVertex(p, middle),
+			colorVertex(p.A(offset), side),
+			colorVertex(p.S(offset), side),
 		)
-	} else if thickness == 1 {
-		gfx.RectangleColor(
-			o.renderer,
-			int(rect.X+0.5),
-			int(rect.Y+0.5),
-			int(rect.X+rect.W+0.5),
-			int(rect.Y+rect.H+0.5),
-			color.toSDL(),
+	}
+	for i := 0; i < segments; i++ {
+		a, b := int32(3*i), int32(3*((i+1)%len(points)))
+		o.indices = append(o.indices,
+			a, b, b+1, a, b+1, a+1,
+			a, b, b+2, a, b+2, a+2,
 		)
-	} else {
-		points := []Vec{
-			{rect.X, rect.Y},
-			{rect.X + rect.W, rect.Y},
-			{rect.X + rect.W, rect.Y + rect.H},
-			{rect.X, rect.Y + rect.H},
-		}
-		o.DrawPolygon(points, thickness, color)
 	}
+	o.renderGeometry(nil)
 }
 
-func (o *rendererOutput) DrawPicture(rect Rect, pic *Picture) {
-	if o.textures[pic.surface] == nil || pic.surface.Flags&staticSurface == 0 {
-		if o.textures[pic.surface] != nil {
-			o.textures[pic.surface].Destroy() // need to destroy old textures to avoid memory leaks
+// colorVertex creates an untextured vertex.
+func colorVertex(pos Vec, color sdl.Color) sdl.Vertex {
+	return sdl.Vertex{
+		Position: sdl.FPoint{X: float32(pos.X), Y: float32(pos.Y)},
+		Color:    color,
+	}
+}
+
+// textureCache keeps the textures a renderer created from surfaces.
+type textureCache struct {
+	textures map[*sdl.Surface]*sdl.Texture
+	versions map[*sdl.Surface]int // versions of static surfaces the textures were created from
+}
+
+// textureCaches are all existing texture caches, so that freeSurface can evict a surface from
+// all of them.
+var textureCaches = make(map[*textureCache]bool)
+
+func newTextureCache() *textureCache {
+	cache := &textureCache{
+		textures: make(map[*sdl.Surface]*sdl.Texture),
+		versions: make(map[*sdl.Surface]int),
+	}
+	textureCaches[cache] = true
+	return cache
+}
+
+// evict destroys the texture of a surface.
+func (c *textureCache) evict(surface *sdl.Surface) {
+	if texture := c.textures[surface]; texture != nil {
+		texture.Destroy()
+	}
+	delete(c.textures, surface)
+	delete(c.versions, surface)
+}
+
+// destroy destroys all textures of the cache. It needs to be called before the renderer is
+// destroyed.
+func (c *textureCache) destroy() {
+	for surface := range c.textures {
+		c.evict(surface)
+	}
+	delete(textureCaches, c)
+}
+
+// texture returns an up-to-date texture of a surface, creating it if necessary.
+func (o *rendererOutput) texture(surface *sdl.Surface) *sdl.Texture {
+	c := o.cache
+	if c.textures[surface] == nil ||
+		surfaceFlags[surface]&staticSurface == 0 ||
+		c.versions[surface] != surfaceVersions[surface] {
+		if c.textures[surface] != nil {
+			c.textures[surface].Destroy() // need to destroy old textures to avoid memory leaks
 		}
 
-		texture, err := o.renderer.CreateTextureFromSurface(pic.surface)
+		texture, err := o.surfaceTexture(surface)
 		if err != nil {
 			panic("failed to create a texture from a surface")
 		}
-		texture.SetBlendMode(sdl.BLENDMODE_BLEND)
-		o.textures[pic.surface] = texture
+		c.textures[surface] = texture
+		c.versions[surface] = surfaceVersions[surface]
+	}
+	return c.textures[surface]
+}
+
+// surfaceTexture creates a texture from a surface. Surfaces of canvases have premultiplied
+// colors, they are uploaded with straight colors, so that they blend with all blend modes and
+// renderers like any other picture. Their surfaces are static, so the colors are only
+// converted again after drawing on them.
+func (o *rendererOutput) surfaceTexture(surface *sdl.Surface) (*sdl.Texture, error) {
+	if surfaceFlags[surface]&premultipliedSurface == 0 {
+		return o.renderer.CreateTextureFromSurface(surface)
+	}
+	straight, err := surface.ConvertFormat(surface.Format.Format, 0)
+	if err != nil {
+		return nil, err
+	}
+	defer straight.Free()
+	unpremultiply(straight)
+	return o.renderer.CreateTextureFromSurface(straight)
+}
+
+// pictureTexture returns an up-to-date texture of a picture. Pictures of target canvases drawn
+// by the same renderer are drawn right from their target textures, which have premultiplied
+// colors, if the blend mode has a premultiplied equivalent. Otherwise they are read back first.
+func (o *rendererOutput) pictureTexture(pic *Picture) (texture *sdl.Texture, premultiplied bool) {
+	if pic.canvas != nil {
+		if pic.canvas.renderer == o.renderer && premultipliedBlendModes[o.blendMode] != 0 {
+			return pic.canvas.target, true
+		}
+		pic.canvas.readBack()
+		o.bind() // reading back may have bound the canvas
+	}
+	return o.texture(pic.surface), false
+}
+
+// setTextureBlendMode sets the blend mode of the output to a texture. Textures are shared
+// by all draw calls, so it needs to be set before every draw.
+func (o *rendererOutput) setTextureBlendMode(texture *sdl.Texture, premultiplied bool) {
+	mode := o.blendMode
+	if premultiplied {
+		mode = premultipliedBlendModes[mode]
+	}
+	if texture.SetBlendMode(sdl.BlendMode(mode)) != nil {
+		texture.SetBlendMode(sdl.BLENDMODE_BLEND) // not supported by the renderer
 	}
+}
 
-	r, g, b, a := o.mask.toSDLRGBA()
+func (o *rendererOutput) DrawPicture(rect Rect, pic *Picture) {
+	o.DrawPictureEx(rect, pic, PictureOptions{Pivot: rect.Size().D(2)})
+}
+
+func (o *rendererOutput) DrawPictureEx(rect Rect, pic *Picture, opts PictureOptions) {
+	o.bind()
+	mask := o.mask
+	if opts.Tint != (Color{}) {
+		mask = mask.Mul(opts.Tint)
+	}
 
-	texture := o.textures[pic.surface]
+	texture, premultiplied := o.pictureTexture(pic)
+	o.setTextureBlendMode(texture, premultiplied)
+	if premultiplied {
+		mask = mask.premultiplied()
+	}
+	r, g, b, a := mask.toSDLRGBA()
 	texture.SetColorMod(r, g, b)
 	texture.SetAlphaMod(a)
 
-	dst := sdl.Rect{
-		X: int32(rect.X + 0.5),
-		Y: int32(rect.Y + 0.5),
-		W: int32(rect.W + 0.5),
-		H: int32(rect.H + 0.5),
+	dst := sdl.FRect{
+		X: float32(rect.X),
+		Y: float32(rect.Y),
+		W: float32(rect.W),
+		H: float32(rect.H),
+	}
+	center := sdl.FPoint{
+		X: float32(opts.Pivot.X),
+		Y: float32(opts.Pivot.Y),
+	}
+	flip := sdl.FLIP_NONE
+	if opts.FlipX {
+		flip |= sdl.FLIP_HORIZONTAL
+	}
+	if opts.FlipY {
+		flip |= sdl.FLIP_VERTICAL
+	}
+	angle := pic.angle + opts.Angle
+	o.renderer.CopyExF(texture, &pic.rect, &dst, angle/math.Pi*180, &center, flip)
+}
+
+func (o *rendererOutput) DrawBatch(batch *Batch) {
+	o.bind()
+	sprites := batch.sprites
+	for len(sprites) > 0 {
+		// consecutive sprites of the same surface go together, a change flushes them
+		run := 1
+		for run < len(sprites) && sprites[run].pic.surface == sprites[0].pic.surface {
+			run++
+		}
+		o.drawSprites(sprites[:run])
+		sprites = sprites[run:]
+	}
+}
+
+// drawSprites draws sprites sharing the same surface in one call to the renderer.
+func (o *rendererOutput) drawSprites(sprites []batchSprite) {
+	// colors go into the vertices, the texture must not modulate them again
+	texture, premultiplied := o.pictureTexture(sprites[0].pic)
+	o.setTextureBlendMode(texture, premultiplied)
+	texture.SetColorMod(255, 255, 255)
+	texture.SetAlphaMod(255)
+
+	o.vertices, o.indices = o.vertices[:0], o.indices[:0]
+	surface := sprites[0].pic.surface
+	w, h := float32(surface.W), float32(surface.H)
+	for _, sprite := range sprites {
+		color := sprite.color.Mul(o.mask)
+		if premultiplied {
+			color = color.premultiplied()
+		}
+		src := sprite.pic.rect
+		u1, v1 := float32(src.X)/w, float32(src.Y)/h
+		u2, v2 := float32(src.X+src.W)/w, float32(src.Y+src.H)/h
+		if sprite.flipX {
+			u1, u2 = u2, u1
+		}
+		if sprite.flipY {
+			v1, v2 = v2, v1
+		}
+
+		r := sprite.rect
+		corners := [4]Vec{{r.X, r.Y}, {r.X + r.W, r.Y}, {r.X + r.W, r.Y + r.H}, {r.X, r.Y + r.H}}
+		uvs := [4]sdl.FPoint{{X: u1, Y: v1}, {X: u2, Y: v1}, {X: u2, Y: v2}, {X: u1, Y: v2}}
+
+		base := int32(len(o.vertices))
+		center := r.Center()
+		for k := range corners {
+			p := corners[k].S(center).Rotated(sprite.angle).A(center)
+			o.vertices = append(o.vertices, sdl.Vertex{
+				Position: sdl.FPoint{X: float32(p.X), Y: float32(p.Y)},
+				Color:    color.toSDL(),
+				TexCoord: uvs[k],
+			})
+		}
+		o.indices = append(o.indices, base, base+1, base+2, base, base+2, base+3)
 	}
-	o.renderer.CopyEx(texture, &pic.rect, &dst, pic.angle/math.Pi*180, nil, sdl.FLIP_NONE)
+
+	o.renderGeometry(texture)
+}
+
+func (o *rendererOutput) DrawText(pos Vec, font Font, text string, color Color) {
+	mask := o.mask
+	o.mask = o.mask.Mul(color)
+	forEachGlyph(pos, font, text, func(rect Rect, pic *Picture) {
+		o.DrawPicture(rect, pic)
+	})
+	o.mask = mask
 }
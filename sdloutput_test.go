@@ -0,0 +1,39 @@
+package gogame
+
+import (
+	"testing"
+
+	"github.com/veandco/go-sdl2/sdl"
+)
+
+func TestViewportRect(t *testing.T) {
+	whole := clipState{viewport: sdl.Rect{W: 100, H: 50}, clip: sdl.Rect{W: 100, H: 50}}
+	view := clipState{
+		viewport:   sdl.Rect{X: 10, Y: 10, W: 30, H: 20},
+		clip:       sdl.Rect{X: 10, Y: 10, W: 30, H: 20},
+		isViewport: true,
+	}
+	clip := func(s clipState) clipState {
+		s.clip, s.isViewport = sdl.Rect{X: s.viewport.X, Y: s.viewport.Y, W: 5, H: 5}, false
+		return s
+	}
+
+	tests := []struct {
+		name   string
+		clips  []clipState
+		want   Rect
+		wantOk bool
+	}{
+		{"nothing pushed", nil, Rect{}, false},
+		{"clip", []clipState{clip(whole)}, Rect{}, false},
+		{"viewport", []clipState{view}, Rect{W: 30, H: 20}, true},
+		{"clip in a viewport", []clipState{view, clip(view)}, Rect{W: 30, H: 20}, true},
+	}
+	for _, test := range tests {
+		o := rendererOutput{clips: test.clips}
+		got, ok := o.viewportRect()
+		if got != test.want || ok != test.wantOk {
+			t.Errorf("%s: got %v, %v, want %v, %v", test.name, got, ok, test.want, test.wantOk)
+		}
+	}
+}
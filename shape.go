@@ -0,0 +1,102 @@
+package gogame
+
+import "math"
+
+// This file approximates shapes with points, so that they can be drawn as polygons and lines.
+
+// rectPoints returns the corners of a rectangle.
+func rectPoints(rect Rect) []Vec {
+	return []Vec{
+		{rect.X, rect.Y},
+		{rect.X + rect.W, rect.Y},
+		{rect.X + rect.W, rect.Y + rect.H},
+		{rect.X, rect.Y + rect.H},
+	}
+}
+
+// arcPoints returns points along an elliptic arc from angle start to angle end (in radians),
+// including both ends. The density of the points depends on the size of the ellipse.
+func arcPoints(center, radius Vec, start, end float64) []Vec {
+	maxRadius := math.Max(math.Abs(radius.X), math.Abs(radius.Y))
+	segments := int(math.Ceil(math.Abs(end-start) * math.Max(maxRadius, 8) / 4))
+	if segments < 2 {
+		segments = 2
+	}
+
+	points := make([]Vec, segments+1)
+	for i := range points {
+		angle := start + (end-start)*float64(i)/float64(segments)
+		points[i] = Vec{
+			X: center.X + radius.X*math.Cos(angle),
+			Y: center.Y + radius.Y*math.Sin(angle),
+		}
+	}
+	return points
+}
+
+// ellipsePoints returns points around a whole ellipse, without repeating the first point.
+func ellipsePoints(center, radius Vec) []Vec {
+	points := arcPoints(center, radius, 0, 2*math.Pi)
+	return points[:len(points)-1]
+}
+
+// roundedRectPoints returns points around a rectangle with corners rounded by a radius.
+// The radius is limited to half of the shorter side.
+func roundedRectPoints(rect Rect, radius float64) []Vec {
+	if rect.W < 0 {
+		rect.X, rect.W = rect.X+rect.W, -rect.W
+	}
+	if rect.H < 0 {
+		rect.Y, rect.H = rect.Y+rect.H, -rect.H
+	}
+	radius = clamp(radius, 0, math.Min(rect.W, rect.H)/2)
+	r := Vec{X: radius, Y: radius}
+
+	corners := []struct {
+		center Vec
+		angle  float64
+	}{
+		{Vec{rect.X + rect.W - radius, rect.Y + radius}, -math.Pi / 2},
+		{Vec{rect.X + rect.W - radius, rect.Y + rect.H - radius}, 0},
+		{Vec{rect.X + radius, rect.Y + rect.H - radius}, math.Pi / 2},
+		{Vec{rect.X + radius, rect.Y + radius}, math.Pi},
+	}
+	var points []Vec
+	for _, corner := range corners {
+		points = append(points, arcPoints(corner.center, r, corner.angle, corner.angle+math.Pi/2)...)
+	}
+	return points
+}
+
+// bezierPoints returns points along a Bézier curve with the specified control points,
+// including both ends.
+func bezierPoints(controls []Vec) []Vec {
+	if len(controls) < 2 {
+		return controls
+	}
+
+	length := 0.0
+	for i := 1; i < len(controls); i++ {
+		length += controls[i].S(controls[i-1]).Len()
+	}
+	segments := int(math.Ceil(length / 4))
+	if segments < 2 {
+		segments = 2
+	}
+
+	points := make([]Vec, segments+1)
+	work := make([]Vec, len(controls))
+	for i := range points {
+		t := float64(i) / float64(segments)
+
+		// de Casteljau's algorithm
+		copy(work, controls)
+		for n := len(work) - 1; n > 0; n-- {
+			for j := 0; j < n; j++ {
+				work[j] = work[j].M(1 - t).A(work[j+1].M(t))
+			}
+		}
+		points[i] = work[0]
+	}
+	return points
+}
@@ -0,0 +1,137 @@
+package gogame
+
+import (
+	"math"
+	"testing"
+)
+
+// onEllipse checks if a point lies on the ellipse with a center and radii.
+func onEllipse(p, center, radius Vec) bool {
+	dx, dy := (p.X-center.X)/radius.X, (p.Y-center.Y)/radius.Y
+	return math.Abs(dx*dx+dy*dy-1) < 1e-9
+}
+
+func TestArcPoints(t *testing.T) {
+	tests := []struct {
+		name        string
+		center      Vec
+		radius      Vec
+		start, end  float64
+		first, last Vec
+	}{
+		{"quarter", Vec{}, Vec{X: 10, Y: 10}, 0, math.Pi / 2,
+			Vec{X: 10, Y: 0}, Vec{X: 0, Y: 10}},
+		{"backwards", Vec{X: 5, Y: 5}, Vec{X: 10, Y: 10}, math.Pi, 0,
+			Vec{X: -5, Y: 5}, Vec{X: 15, Y: 5}},
+		{"elliptic", Vec{X: 1, Y: 2}, Vec{X: 20, Y: 10}, -math.Pi / 2, math.Pi,
+			Vec{X: 1, Y: -8}, Vec{X: -19, Y: 2}},
+		{"tiny", Vec{}, Vec{X: 0.1, Y: 0.1}, 0, 0.01,
+			Vec{X: 0.1, Y: 0}, Vec{X: 0.1 * math.Cos(0.01), Y: 0.1 * math.Sin(0.01)}},
+	}
+	for _, test := range tests {
+		points := arcPoints(test.center, test.radius, test.start, test.end)
+		if len(points) < 3 {
+			t.Errorf("%s: got %d points, want at least 3", test.name, len(points))
+			continue
+		}
+		if first := points[0]; !vecsClose(first, test.first) {
+			t.Errorf("%s: got the first point %v, want %v", test.name, first, test.first)
+		}
+		if last := points[len(points)-1]; !vecsClose(last, test.last) {
+			t.Errorf("%s: got the last point %v, want %v", test.name, last, test.last)
+		}
+		for _, p := range points {
+			if !onEllipse(p, test.center, test.radius) {
+				t.Errorf("%s: got %v, which is off the arc", test.name, p)
+				break
+			}
+		}
+	}
+}
+
+func TestEllipsePoints(t *testing.T) {
+	center, radius := Vec{X: 3, Y: 4}, Vec{X: 30, Y: 10}
+	points := ellipsePoints(center, radius)
+	if !vecsClose(points[0], Vec{X: 33, Y: 4}) {
+		t.Errorf("got the first point %v, want %v", points[0], Vec{X: 33, Y: 4})
+	}
+	// the polygon is closed implicitly, the first point isn't repeated at the end
+	if last := points[len(points)-1]; vecsClose(last, points[0]) {
+		t.Errorf("got the first point repeated at the end")
+	}
+	for _, p := range points {
+		if !onEllipse(p, center, radius) {
+			t.Errorf("got %v, which is off the ellipse", p)
+			break
+		}
+	}
+}
+
+func TestRoundedRectPoints(t *testing.T) {
+	tests := []struct {
+		name   string
+		rect   Rect
+		radius float64
+		bounds Rect
+		first  Vec // the top end of the top-right corner
+	}{
+		{"rounded", Rect{X: 10, Y: 20, W: 40, H: 30}, 5,
+			Rect{X: 10, Y: 20, W: 40, H: 30}, Vec{X: 45, Y: 20}},
+		{"sharp", Rect{W: 40, H: 30}, 0, Rect{W: 40, H: 30}, Vec{X: 40, Y: 0}},
+		{"radius clamped", Rect{W: 40, H: 30}, 100, Rect{W: 40, H: 30}, Vec{X: 25, Y: 0}},
+		{"negative radius", Rect{W: 40, H: 30}, -5, Rect{W: 40, H: 30}, Vec{X: 40, Y: 0}},
+		{"negative size", Rect{X: 50, Y: 50, W: -40, H: -30}, 5,
+			Rect{X: 10, Y: 20, W: 40, H: 30}, Vec{X: 45, Y: 20}},
+	}
+	for _, test := range tests {
+		points := roundedRectPoints(test.rect, test.radius)
+		if !vecsClose(points[0], test.first) {
+			t.Errorf("%s: got the first point %v, want %v", test.name, points[0], test.first)
+		}
+		min, max := points[0], points[0]
+		for _, p := range points {
+			min = Vec{X: math.Min(min.X, p.X), Y: math.Min(min.Y, p.Y)}
+			max = Vec{X: math.Max(max.X, p.X), Y: math.Max(max.Y, p.Y)}
+		}
+		bounds := Rect{X: min.X, Y: min.Y, W: max.X - min.X, H: max.Y - min.Y}
+		if !vecsClose(bounds.Pos(), test.bounds.Pos()) ||
+			!vecsClose(bounds.Size(), test.bounds.Size()) {
+			t.Errorf("%s: got bounds %v, want %v", test.name, bounds, test.bounds)
+		}
+	}
+}
+
+func TestBezierPoints(t *testing.T) {
+	tests := []struct {
+		name     string
+		controls []Vec
+		middle   Vec // at t = 0.5, the curves are long enough for an even number of segments
+	}{
+		{"line", []Vec{{X: 0, Y: 0}, {X: 8, Y: 0}}, Vec{X: 4, Y: 0}},
+		{"quadratic", []Vec{{X: 0, Y: 0}, {X: 0, Y: 16}, {X: 16, Y: 16}}, Vec{X: 4, Y: 12}},
+		{"cubic", []Vec{{X: 0, Y: 0}, {X: 0, Y: 16}, {X: 16, Y: 16}, {X: 16, Y: 0}},
+			Vec{X: 8, Y: 12}},
+	}
+	for _, test := range tests {
+		points := bezierPoints(test.controls)
+		if len(points)%2 == 0 {
+			t.Errorf("%s: got %d points, want an odd number", test.name, len(points))
+			continue
+		}
+		if first := points[0]; !vecsClose(first, test.controls[0]) {
+			t.Errorf("%s: got the first point %v, want %v", test.name, first, test.controls[0])
+		}
+		last, want := points[len(points)-1], test.controls[len(test.controls)-1]
+		if !vecsClose(last, want) {
+			t.Errorf("%s: got the last point %v, want %v", test.name, last, want)
+		}
+		if middle := points[len(points)/2]; !vecsClose(middle, test.middle) {
+			t.Errorf("%s: got the middle point %v, want %v", test.name, middle, test.middle)
+		}
+	}
+
+	single := []Vec{{X: 1, Y: 2}}
+	if got := bezierPoints(single); len(got) != 1 || got[0] != single[0] {
+		t.Errorf("single control point: got %v, want %v", got, single)
+	}
+}
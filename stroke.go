@@ -0,0 +1,335 @@
+package gogame
+
+import "math"
+
+// LineCap specifies the shape of the ends of open lines.
+type LineCap int
+
+// Enumeration of all line caps.
+const (
+	// CapButt ends a line exactly at its end point.
+	CapButt LineCap = iota
+
+	// CapRound ends a line with a half circle around its end point.
+	CapRound
+
+	// CapSquare ends a line with a half square around its end point.
+	CapSquare
+)
+
+// LineJoin specifies the shape of the corners where two lines of a polyline meet.
+type LineJoin int
+
+// Enumeration of all line joins.
+const (
+	// JoinMiter extends the outer edges of the lines until they meet in a sharp corner.
+	// Corners too sharp for MiterLimit are beveled instead.
+	JoinMiter LineJoin = iota
+
+	// JoinRound rounds the corners.
+	JoinRound
+
+	// JoinBevel cuts the corners off.
+	JoinBevel
+)
+
+// Stroke specifies how DrawPolyline draws lines.
+type Stroke struct {
+	// Thickness is the width of the lines. Lines with thickness 1 or less are drawn as thin
+	// lines, which have no caps and joins.
+	Thickness float64
+
+	// Cap is the shape of the ends of open polylines and dashes.
+	Cap LineCap
+
+	// Join is the shape of the corners.
+	Join LineJoin
+
+	// MiterLimit is how many times a miter corner may stick out further than half of the
+	// thickness, sharper corners are beveled. 0 means 4.
+	MiterLimit float64
+
+	// Dashes are the lengths of alternating dashes and gaps, starting with a dash. If there's
+	// an odd number of lengths, they are repeated to make an even number. No dashes means
+	// a solid line.
+	Dashes []float64
+
+	// DashOffset is how far into the dash pattern the polyline starts.
+	DashOffset float64
+}
+
+// thickStroke is the stroke of lines drawn by functions which only take a thickness, such as
+// DrawLine and the outlines of DrawPolygon: round joins and butt caps.
+func thickStroke(thickness float64) Stroke {
+	return Stroke{Thickness: thickness, Join: JoinRound}
+}
+
+// strokePolylines splits a polyline into the dashes of a stroke. Each dash is an open
+// polyline. Without dashes, the polyline is returned as it is.
+func strokePolylines(points []Vec, closed bool, stroke Stroke) (polylines [][]Vec, isClosed bool) {
+	total := 0.0
+	for _, dash := range stroke.Dashes {
+		total += math.Max(dash, 0)
+	}
+	if total == 0 {
+		return [][]Vec{points}, closed
+	}
+
+	pattern := stroke.Dashes
+	if len(pattern)%2 == 1 {
+		pattern = append(append([]float64(nil), pattern...), pattern...)
+		total *= 2
+	}
+
+	// find where in the pattern the polyline starts
+	index, left := 0, math.Mod(stroke.DashOffset, total)
+	if left < 0 {
+		left += total
+	}
+	for left >= math.Max(pattern[index], 0) {
+		left -= math.Max(pattern[index], 0)
+		index = (index + 1) % len(pattern)
+	}
+	left = math.Max(pattern[index], 0) - left
+
+	segments := len(points) - 1
+	if closed {
+		segments = len(points)
+	}
+	startsInDash := index%2 == 0
+	var dash []Vec
+	if startsInDash && len(points) > 0 {
+		dash = []Vec{points[0]}
+	}
+	for i := 0; i < segments; i++ {
+		a, b := points[i], points[(i+1)%len(points)]
+		length := b.S(a).Len()
+		pos := 0.0
+		for length-pos > left {
+			pos += left
+			p := a.A(b.S(a).M(pos / length))
+			if index%2 == 0 {
+				polylines = append(polylines, append(dash, p))
+				dash = nil
+			} else {
+				dash = []Vec{p}
+			}
+			index = (index + 1) % len(pattern)
+			left = math.Max(pattern[index], 0)
+		}
+		left -= length - pos
+		if index%2 == 0 {
+			dash = append(dash, b)
+		}
+	}
+	switch {
+	case len(dash) == 0:
+	case closed && startsInDash && len(polylines) == 0:
+		return [][]Vec{points}, true // one dash all around
+	case closed && startsInDash:
+		// the last dash goes on into the first one
+		polylines[0] = append(dash, polylines[0][1:]...)
+	default:
+		polylines = append(polylines, dash)
+	}
+	return polylines, false
+}
+
+// strokeSection is a cross-section of a thick polyline: a point on each edge of the stroke,
+// and the one pixel long directions in which its anti-aliasing fringe goes out from them.
+type strokeSection struct {
+	left, right       Vec
+	leftOut, rightOut Vec
+}
+
+// section returns a cross-section between points of the edges around a center point, with
+// the fringes going straight away from the center.
+func section(center, left, right Vec, half float64) strokeSection {
+	return strokeSection{
+		left:     left,
+		right:    right,
+		leftOut:  left.S(center).D(half),
+		rightOut: right.S(center).D(half),
+	}
+}
+
+// strokeStrip returns cross-sections along a thick polyline drawn with a stroke. Every two
+// consecutive cross-sections enclose a quad of the stroke, so the stroke is a strip of
+// triangles which don't overlap, except on the inner side of joins sharper than the lines
+// around them are long. If the polyline is closed, the last cross-section is the same as the
+// first one. FlatEnds reports if the ends (butt or square caps) need fringes across them.
+func strokeStrip(points []Vec, closed bool, stroke Stroke) (strip []strokeSection, flatEnds bool) {
+	points = uniquePoints(points, closed)
+
+	half := stroke.Thickness / 2
+	if len(points) == 0 || half <= 0 {
+		return nil, false
+	}
+	if len(points) == 1 {
+		if stroke.Cap != CapRound {
+			return nil, false
+		}
+		// a dot, both caps of a line with no length
+		dir := Vec{X: 1, Y: 0}
+		strip = capSections(points[0], dir.M(-1), half, CapRound, true)
+		return append(strip, capSections(points[0], dir, half, CapRound, false)...), false
+	}
+	if len(points) == 2 && closed {
+		// a closed line would go back over itself, its joins become the caps
+		closed, stroke.Cap = false, CapButt
+		if stroke.Join == JoinRound {
+			stroke.Cap = CapRound
+		}
+	}
+
+	segments := len(points) - 1
+	if closed {
+		segments = len(points)
+	}
+	dirs := make([]Vec, segments)
+	lengths := make([]float64, segments)
+	for i := range dirs {
+		a, b := points[i], points[(i+1)%len(points)]
+		lengths[i] = b.S(a).Len()
+		dirs[i] = b.S(a).D(lengths[i])
+	}
+
+	join := func(i int) []strokeSection {
+		prev := (i + segments - 1) % segments
+		return joinSections(points[i], dirs[prev], dirs[i], lengths[prev], lengths[i], half, stroke)
+	}
+
+	if closed {
+		first := join(0)
+		strip = append(strip, first[len(first)-1])
+		for i := 1; i < len(points); i++ {
+			strip = append(strip, join(i)...)
+		}
+		return append(strip, first...), false
+	}
+
+	strip = capSections(points[0], dirs[0].M(-1), half, stroke.Cap, true)
+	for i := 1; i < len(points)-1; i++ {
+		strip = append(strip, join(i)...)
+	}
+	last := points[len(points)-1]
+	strip = append(strip, capSections(last, dirs[segments-1], half, stroke.Cap, false)...)
+	return strip, stroke.Cap != CapRound
+}
+
+// uniquePoints returns the points of a polyline without repeated points, which have no
+// direction and would only break joins.
+func uniquePoints(points []Vec, closed bool) []Vec {
+	var unique []Vec
+	for _, p := range points {
+		if len(unique) == 0 || p != unique[len(unique)-1] {
+			unique = append(unique, p)
+		}
+	}
+	if closed && len(unique) > 1 && unique[0] == unique[len(unique)-1] {
+		unique = unique[:len(unique)-1]
+	}
+	return unique
+}
+
+// capSections returns the cross-sections of a cap at the end point p of a line going in
+// direction dir (outwards). At the start of a line, the sections go towards the line.
+func capSections(p, dir Vec, half float64, lineCap LineCap, start bool) []strokeSection {
+	// left and right as seen going along the line
+	normal := Vec{X: -dir.Y, Y: dir.X}
+	if start {
+		normal = normal.M(-1)
+	}
+
+	var sections []strokeSection
+	switch lineCap {
+	case CapRound:
+		steps := len(arcPoints(p, Vec{X: half, Y: half}, 0, math.Pi/2)) - 1
+		for i := 0; i <= steps; i++ {
+			angle := math.Pi / 2 * float64(i) / float64(steps)
+			if !start {
+				angle = math.Pi/2 - angle
+			}
+			along, across := dir.M(math.Cos(angle)*half), normal.M(math.Sin(angle)*half)
+			sections = append(sections, section(p, p.A(along).A(across), p.A(along).S(across), half))
+		}
+		return sections
+	case CapSquare:
+		p = p.A(dir.M(half))
+	}
+	return []strokeSection{{
+		left:     p.A(normal.M(half)),
+		right:    p.S(normal.M(half)),
+		leftOut:  normal,
+		rightOut: normal.M(-1),
+	}}
+}
+
+// joinSections returns the cross-sections of a corner at p where a line going in direction d0
+// turns to direction d1. The lengths of the lines limit how far the inner corner may go.
+func joinSections(p, d0, d1 Vec, length0, length1, half float64, stroke Stroke) []strokeSection {
+	n0, n1 := Vec{X: -d0.Y, Y: d0.X}, Vec{X: -d1.Y, Y: d1.X}
+	turn := cross(d0, d1)
+	if math.Abs(turn) < 1e-9 && dot(d0, d1) > 0 {
+		return []strokeSection{section(p, p.A(n1.M(half)), p.S(n1.M(half)), half)}
+	}
+
+	// the inner side is the one the polyline turns to, the left one if turn is positive
+	side := math.Copysign(1, turn)
+	o0, o1 := n0.M(-side*half), n1.M(-side*half) // outer edges of the lines
+
+	// the inner edges meet at the opposite of the miter, unless the lines are too short
+	var miter Vec
+	if 1+dot(n0, n1) > 1e-9 {
+		miter = n0.A(n1).M(half / (1 + dot(n0, n1)))
+	}
+	innerStart, innerMiddle, innerEnd := p.S(o0), p, p.S(o1)
+	if miter != (Vec{}) &&
+		math.Abs(dot(miter, d0)) <= length0 && math.Abs(dot(miter, d1)) <= length1 {
+		inner := p.A(miter.M(side))
+		innerStart, innerMiddle, innerEnd = inner, inner, inner
+	}
+
+	var outer []Vec
+	switch stroke.Join {
+	case JoinMiter:
+		limit := stroke.MiterLimit
+		if limit == 0 {
+			limit = 4
+		}
+		if miter != (Vec{}) && miter.Len() <= limit*half {
+			outer = []Vec{p.A(o0), p.S(miter.M(side)), p.A(o1)}
+			if innerStart == innerEnd {
+				outer = outer[1:2] // the edges of the lines meet in one cross-section
+			}
+		} else {
+			outer = []Vec{p.A(o0), p.A(o1)}
+		}
+	case JoinRound:
+		sweep := math.Atan2(cross(o0, o1), dot(o0, o1))
+		if o0.A(o1).Len() < 1e-9*half {
+			// going back, the round corner goes around the front
+			sweep = math.Copysign(math.Pi, dot(Vec{X: -o0.Y, Y: o0.X}, d0))
+		}
+		start := math.Atan2(o0.Y, o0.X)
+		outer = arcPoints(p, Vec{X: half, Y: half}, start, start+sweep)
+	default:
+		outer = []Vec{p.A(o0), p.A(o1)}
+	}
+
+	sections := make([]strokeSection, len(outer))
+	for i, point := range outer {
+		inner := innerMiddle
+		if i == 0 {
+			inner = innerStart
+		} else if i == len(outer)-1 {
+			inner = innerEnd
+		}
+		if side > 0 {
+			sections[i] = section(p, inner, point, half)
+		} else {
+			sections[i] = section(p, point, inner, half)
+		}
+	}
+	return sections
+}
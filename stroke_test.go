@@ -0,0 +1,138 @@
+package gogame
+
+import (
+	"math"
+	"reflect"
+	"testing"
+)
+
+func TestStrokeDashes(t *testing.T) {
+	line := []Vec{{X: 0, Y: 0}, {X: 10, Y: 0}}
+	square := rectPoints(Rect{W: 10, H: 10})
+
+	tests := []struct {
+		name       string
+		points     []Vec
+		closed     bool
+		dashes     []float64
+		offset     float64
+		want       [][]Vec
+		wantClosed bool
+	}{
+		{"solid", line, false, nil, 0, [][]Vec{line}, false},
+		{"solid closed", square, true, nil, 0, [][]Vec{square}, true},
+		{"zero pattern", line, false, []float64{0, -1}, 0, [][]Vec{line}, false},
+		{"dashes", line, false, []float64{3, 1}, 0, [][]Vec{
+			{{X: 0, Y: 0}, {X: 3, Y: 0}},
+			{{X: 4, Y: 0}, {X: 7, Y: 0}},
+			{{X: 8, Y: 0}, {X: 10, Y: 0}},
+		}, false},
+		{"odd pattern", line, false, []float64{4}, 0, [][]Vec{
+			{{X: 0, Y: 0}, {X: 4, Y: 0}},
+			{{X: 8, Y: 0}, {X: 10, Y: 0}},
+		}, false},
+		{"offset into a gap", line, false, []float64{3, 2}, 4, [][]Vec{
+			{{X: 1, Y: 0}, {X: 4, Y: 0}},
+			{{X: 6, Y: 0}, {X: 9, Y: 0}},
+		}, false},
+		{"negative offset", line, false, []float64{3, 2}, -2, [][]Vec{
+			{{X: 2, Y: 0}, {X: 5, Y: 0}},
+			{{X: 7, Y: 0}, {X: 10, Y: 0}},
+		}, false},
+		{"around a corner", []Vec{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 5}}, false,
+			[]float64{4, 2}, 0, [][]Vec{
+				{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 2}},
+				{{X: 2, Y: 4}, {X: 2, Y: 5}},
+			}, false},
+		{"closed joins the last dash to the first", square, true, []float64{15, 5}, 0,
+			[][]Vec{
+				{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 5}},
+				{{X: 10, Y: 10}, {X: 0, Y: 10}, {X: 0, Y: 5}},
+			}, false},
+		{"closed joins across the start", square, true, []float64{10, 10}, 5, [][]Vec{
+			{{X: 0, Y: 5}, {X: 0, Y: 0}, {X: 5, Y: 0}},
+			{{X: 10, Y: 5}, {X: 10, Y: 10}, {X: 5, Y: 10}},
+		}, false},
+		{"closed in one dash", square, true, []float64{50, 5}, 0, [][]Vec{square}, true},
+	}
+
+	for _, test := range tests {
+		stroke := Stroke{Thickness: 2, Dashes: test.dashes, DashOffset: test.offset}
+		got, closed := strokePolylines(test.points, test.closed, stroke)
+		if !reflect.DeepEqual(got, test.want) || closed != test.wantClosed {
+			t.Errorf("%s: got %v (closed %v), want %v (closed %v)", test.name, got, closed,
+				test.want, test.wantClosed)
+		}
+	}
+}
+
+func TestStrokeStrip(t *testing.T) {
+	corner := []Vec{{X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 10}}
+
+	tests := []struct {
+		name     string
+		points   []Vec
+		closed   bool
+		stroke   Stroke
+		sections int
+		flatEnds bool
+	}{
+		{"butt line", []Vec{{X: 0, Y: 0}, {X: 10, Y: 0}}, false, Stroke{Thickness: 4}, 2, true},
+		{"miter corner", corner, false, Stroke{Thickness: 4}, 3, true},
+		{"bevel corner", corner, false, Stroke{Thickness: 4, Join: JoinBevel}, 4, true},
+		{"miter over the limit", corner, false,
+			Stroke{Thickness: 4, MiterLimit: 1.2}, 4, true},
+		{"closed square", rectPoints(Rect{W: 10, H: 10}), true, Stroke{Thickness: 4}, 5, false},
+		{"repeated points", []Vec{{X: 0, Y: 0}, {X: 0, Y: 0}, {X: 10, Y: 0}, {X: 10, Y: 0}},
+			false, Stroke{Thickness: 4}, 2, true},
+		{"no thickness", corner, false, Stroke{}, 0, false},
+		{"square dot", []Vec{{X: 1, Y: 1}}, false, Stroke{Thickness: 4, Cap: CapSquare}, 0, false},
+	}
+
+	for _, test := range tests {
+		strip, flatEnds := strokeStrip(test.points, test.closed, test.stroke)
+		if len(strip) != test.sections || flatEnds != test.flatEnds {
+			t.Errorf("%s: got %d sections (flat ends %v), want %d (flat ends %v)", test.name,
+				len(strip), flatEnds, test.sections, test.flatEnds)
+			continue
+		}
+		if test.closed && strip[0] != strip[len(strip)-1] {
+			t.Errorf("%s: got first section %v and last %v, want the same", test.name, strip[0],
+				strip[len(strip)-1])
+		}
+	}
+
+	// the edges of a straight line are half of the thickness away, the left one on the left
+	strip, _ := strokeStrip([]Vec{{X: 0, Y: 0}, {X: 10, Y: 0}}, false, Stroke{Thickness: 4})
+	want := strokeSection{
+		left:     Vec{X: 10, Y: 2},
+		right:    Vec{X: 10, Y: -2},
+		leftOut:  Vec{X: 0, Y: 1},
+		rightOut: Vec{X: 0, Y: -1},
+	}
+	if strip[1] != want {
+		t.Errorf("end of a line: got %v, want %v", strip[1], want)
+	}
+
+	// a miter corner meets the outer edges, and the inner edges meet at the opposite corner
+	strip, _ = strokeStrip(corner, false, Stroke{Thickness: 4})
+	if outer, inner := strip[1].right, strip[1].left; !vecsClose(outer, Vec{X: 12, Y: -2}) ||
+		!vecsClose(inner, Vec{X: 8, Y: 2}) {
+		t.Errorf("miter corner: got outer %v and inner %v, want (12, -2) and (8, 2)", outer, inner)
+	}
+
+	// round caps and joins go around at half of the thickness
+	strip, _ = strokeStrip(corner, false, Stroke{Thickness: 4, Cap: CapRound, Join: JoinRound})
+	for _, s := range strip {
+		for _, p := range []Vec{s.left, s.right} {
+			d := math.Min(math.Min(p.S(corner[0]).Len(), p.S(corner[1]).Len()), p.S(corner[2]).Len())
+			if d > 2+1e-9 && math.Abs(p.X-10) > 2+1e-9 && math.Abs(p.Y) > 2+1e-9 {
+				t.Errorf("round stroke: got %v further than 2 from the polyline", p)
+			}
+		}
+	}
+}
+
+func vecsClose(u, v Vec) bool {
+	return u.S(v).Len() < 1e-9
+}
@@ -0,0 +1,347 @@
+package gogame
+
+import (
+	"strconv"
+	"strings"
+)
+
+// TextAlign specifies how lines of text are aligned horizontally.
+type TextAlign int
+
+// Enumeration of all text alignments.
+const (
+	AlignLeft TextAlign = iota
+	AlignCenter
+	AlignRight
+	AlignJustify
+)
+
+// TextOptions configures how NewTextLayout lays out text.
+type TextOptions struct {
+	// MaxWidth is the width at which lines are wrapped. Lines are broken between words if
+	// possible. If MaxWidth is 0, lines are only broken at '\n'.
+	MaxWidth float64
+
+	// Align aligns the lines within MaxWidth, or within the widest line if MaxWidth is 0.
+	// Justified text is aligned left on the last line of each paragraph.
+	Align TextAlign
+
+	// LineSpacing multiplies the line height of the font. 0 means 1.
+	LineSpacing float64
+
+	// Markup turns on inline markup: [color=red]...[/color] (a name from Colors or #rrggbb or
+	// #rrggbbaa) and [b]...[/b] for bold. Tags can be nested. Write [[ for a literal '['.
+	Markup bool
+
+	// Bold is the font used for bold text. If it's nil, bold text is drawn with the regular
+	// font twice, one pixel apart.
+	Bold Font
+}
+
+// NewTextLayout lays out text with a font. The layout can be drawn over and over again without
+// laying the text out again, so static text should be laid out only once.
+func NewTextLayout(font Font, text string, opts TextOptions) *TextLayout {
+	if opts.LineSpacing == 0 {
+		opts.LineSpacing = 1
+	}
+
+	layout := &TextLayout{
+		lineHeight: font.LineHeight() * opts.LineSpacing,
+	}
+
+	chars := []layoutChar{}
+	if opts.Markup {
+		chars = parseMarkup(text)
+	} else {
+		for _, r := range text {
+			chars = append(chars, layoutChar{r: r})
+		}
+	}
+	for i := range chars {
+		chars[i].font = font
+		if chars[i].bold && opts.Bold != nil {
+			chars[i].font = opts.Bold
+		}
+		_, _, chars[i].advance = chars[i].font.Glyph(chars[i].r)
+		if chars[i].bold && opts.Bold == nil {
+			chars[i].fakeBold = true
+			chars[i].advance++
+		}
+	}
+
+	var lines []layoutLine
+	for _, paragraph := range splitChars(chars, '\n') {
+		paragraphLines := wrapChars(paragraph, opts.MaxWidth)
+		for i := range paragraphLines {
+			lines = append(lines, layoutLine{
+				chars: paragraphLines[i],
+				last:  i == len(paragraphLines)-1,
+			})
+		}
+	}
+
+	for i := range lines {
+		lines[i].width = measureChars(lines[i].chars)
+		if lines[i].width > layout.size.X {
+			layout.size.X = lines[i].width
+		}
+	}
+	area := opts.MaxWidth
+	if area == 0 {
+		area = layout.size.X
+	}
+	if opts.MaxWidth > 0 && opts.Align != AlignLeft {
+		layout.size.X = opts.MaxWidth
+	}
+	layout.size.Y = float64(len(lines)) * layout.lineHeight
+
+	for i, line := range lines {
+		x, gap := 0.0, 0.0
+		switch opts.Align {
+		case AlignCenter:
+			x = (area - line.width) / 2
+		case AlignRight:
+			x = area - line.width
+		case AlignJustify:
+			spaces := 0
+			for _, c := range line.chars {
+				if c.r == ' ' {
+					spaces++
+				}
+			}
+			if !line.last && spaces > 0 {
+				gap = (area - line.width) / float64(spaces)
+			}
+		}
+
+		y := float64(i) * layout.lineHeight
+		for j, c := range line.chars {
+			if j > 0 && c.bold == line.chars[j-1].bold { // no kerning across fonts
+				x += c.font.Kerning(line.chars[j-1].r, c.r)
+			}
+			layout.glyphs = append(layout.glyphs, layoutGlyph{
+				layoutChar: c,
+				pos:        Vec{X: x, Y: y},
+			})
+			x += c.advance
+			if c.r == ' ' {
+				x += gap
+			}
+		}
+	}
+
+	return layout
+}
+
+// MeasureText returns the size of text drawn with a font without any wrapping.
+func MeasureText(font Font, text string) Vec {
+	return NewTextLayout(font, text, TextOptions{}).Size()
+}
+
+// TextLayout is text laid out with a font, ready to be drawn. Create one with NewTextLayout.
+type TextLayout struct {
+	glyphs     []layoutGlyph
+	lineHeight float64
+	size       Vec
+}
+
+type layoutChar struct {
+	r        rune
+	font     Font
+	advance  float64
+	color    Color
+	colored  bool
+	bold     bool
+	fakeBold bool
+}
+
+type layoutGlyph struct {
+	layoutChar
+	pos Vec
+}
+
+type layoutLine struct {
+	chars []layoutChar
+	width float64
+	last  bool // last line of a paragraph
+}
+
+// Size returns the width and height of the laid out text.
+func (l *TextLayout) Size() Vec {
+	return l.size
+}
+
+// Len returns the number of runes in the layout (not counting line breaks). This is the count
+// at which DrawReveal draws the whole text.
+func (l *TextLayout) Len() int {
+	return len(l.glyphs)
+}
+
+// Draw draws the whole text onto a video output. Pos is the top-left corner of the layout.
+// Text without a markup color is drawn with the provided color.
+func (l *TextLayout) Draw(out VideoOutput, pos Vec, color Color) {
+	l.DrawReveal(out, pos, color, len(l.glyphs))
+}
+
+// DrawReveal draws only the first count runes of the text, which is useful for typewriter-style
+// dialogues.
+func (l *TextLayout) DrawReveal(out VideoOutput, pos Vec, color Color, count int) {
+	if count > len(l.glyphs) {
+		count = len(l.glyphs)
+	}
+	for _, g := range l.glyphs[:count] {
+		if g.r == ' ' {
+			continue
+		}
+		c := color
+		if g.colored {
+			c = g.color
+			c.A *= color.A
+		}
+		text := string(g.r)
+		out.DrawText(pos.A(g.pos), g.font, text, c)
+		if g.fakeBold {
+			out.DrawText(pos.A(g.pos).A(Vec{X: 1, Y: 0}), g.font, text, c)
+		}
+	}
+}
+
+// splitChars splits chars at every separator, dropping the separators.
+func splitChars(chars []layoutChar, sep rune) [][]layoutChar {
+	var parts [][]layoutChar
+	start := 0
+	for i, c := range chars {
+		if c.r == sep {
+			parts = append(parts, chars[start:i])
+			start = i + 1
+		}
+	}
+	return append(parts, chars[start:])
+}
+
+// wrapChars breaks a paragraph into lines no wider than maxWidth, between words if possible.
+// Spaces at line breaks are dropped.
+func wrapChars(chars []layoutChar, maxWidth float64) [][]layoutChar {
+	if maxWidth <= 0 {
+		return [][]layoutChar{chars}
+	}
+
+	var lines [][]layoutChar
+	var line []layoutChar
+	for _, c := range chars {
+		if c.r == ' ' && len(line) == 0 && len(lines) > 0 {
+			continue // don't start a wrapped line with a space
+		}
+		line = append(line, c)
+		if c.r == ' ' || measureChars(line) <= maxWidth || len(line) == 1 {
+			continue
+		}
+
+		// break after the last space, or before this rune if the word doesn't fit at all
+		split := len(line) - 1
+		for i := len(line) - 1; i > 0; i-- {
+			if line[i-1].r == ' ' {
+				split = i
+				break
+			}
+		}
+		lines = append(lines, trimSpaces(line[:split]))
+		line = append([]layoutChar(nil), line[split:]...)
+	}
+	return append(lines, trimSpaces(line))
+}
+
+func trimSpaces(chars []layoutChar) []layoutChar {
+	for len(chars) > 0 && chars[len(chars)-1].r == ' ' {
+		chars = chars[:len(chars)-1]
+	}
+	return chars
+}
+
+func measureChars(chars []layoutChar) float64 {
+	width := 0.0
+	for i, c := range chars {
+		if i > 0 && c.bold == chars[i-1].bold {
+			width += c.font.Kerning(chars[i-1].r, c.r)
+		}
+		width += c.advance
+	}
+	return width
+}
+
+// parseMarkup turns text with markup tags into styled runes. Unknown tags are kept as text.
+func parseMarkup(text string) []layoutChar {
+	var (
+		chars  []layoutChar
+		colors []Color
+		bold   int
+	)
+
+	runes := []rune(text)
+	for i := 0; i < len(runes); i++ {
+		if runes[i] == '[' && i+1 < len(runes) && runes[i+1] == '[' {
+			i++
+		} else if runes[i] == '[' {
+			end := i + 1
+			for end < len(runes) && runes[end] != ']' {
+				end++
+			}
+			if end < len(runes) {
+				tag := string(runes[i+1 : end])
+				known := true
+				switch {
+				case tag == "b":
+					bold++
+				case tag == "/b" && bold > 0:
+					bold--
+				case strings.HasPrefix(tag, "color="):
+					color, ok := parseColor(strings.TrimPrefix(tag, "color="))
+					if ok {
+						colors = append(colors, color)
+					} else {
+						known = false
+					}
+				case tag == "/color" && len(colors) > 0:
+					colors = colors[:len(colors)-1]
+				default:
+					known = false
+				}
+				if known {
+					i = end
+					continue
+				}
+			}
+		}
+
+		c := layoutChar{r: runes[i], bold: bold > 0}
+		if len(colors) > 0 {
+			c.color, c.colored = colors[len(colors)-1], true
+		}
+		chars = append(chars, c)
+	}
+
+	return chars
+}
+
+// parseColor parses a color name from Colors, or a #rrggbb or #rrggbbaa hex color.
+func parseColor(s string) (Color, bool) {
+	if color, ok := Colors[s]; ok {
+		return color, true
+	}
+	if !strings.HasPrefix(s, "#") || (len(s) != 7 && len(s) != 9) {
+		return Color{}, false
+	}
+	if len(s) == 7 {
+		s += "ff"
+	}
+	n, err := strconv.ParseUint(s[1:], 16, 32)
+	if err != nil {
+		return Color{}, false
+	}
+	return Color{
+		R: float64(n>>24&0xff) / 255,
+		G: float64(n>>16&0xff) / 255,
+		B: float64(n>>8&0xff) / 255,
+		A: float64(n&0xff) / 255,
+	}, true
+}
@@ -0,0 +1,165 @@
+package gogame
+
+import (
+	"reflect"
+	"testing"
+)
+
+// monoFont is a font where every rune is 10 units wide and lines are 20 units high.
+type monoFont struct{}
+
+func (monoFont) Glyph(r rune) (*Picture, Rect, float64) { return nil, Rect{W: 10, H: 20}, 10 }
+func (monoFont) Kerning(a, b rune) float64              { return 0 }
+func (monoFont) LineHeight() float64                    { return 20 }
+
+// layoutLines returns the lines of a layout as strings, with the x of their first rune.
+func layoutLines(l *TextLayout) (lines []string, xs []float64) {
+	y := -1.0
+	for _, g := range l.glyphs {
+		if g.pos.Y != y {
+			y = g.pos.Y
+			lines = append(lines, "")
+			xs = append(xs, g.pos.X)
+		}
+		lines[len(lines)-1] += string(g.r)
+	}
+	return lines, xs
+}
+
+func TestTextWrap(t *testing.T) {
+	tests := []struct {
+		text     string
+		maxWidth float64
+		want     []string
+	}{
+		{"hello world", 0, []string{"hello world"}},
+		{"hello world", 110, []string{"hello world"}},
+		{"hello world", 100, []string{"hello", "world"}},
+		{"hello   world", 60, []string{"hello", "world"}},
+		{"a b c d", 30, []string{"a b", "c d"}},
+		{"abcdefgh", 30, []string{"abc", "def", "gh"}},
+		{"one\ntwo three", 50, []string{"one", "two", "three"}},
+		{"x", 5, []string{"x"}},
+	}
+	for _, test := range tests {
+		l := NewTextLayout(monoFont{}, test.text, TextOptions{MaxWidth: test.maxWidth})
+		if got, _ := layoutLines(l); !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q wrapped at %v: got %q, want %q", test.text, test.maxWidth, got, test.want)
+		}
+	}
+}
+
+func TestTextAlign(t *testing.T) {
+	tests := []struct {
+		align    TextAlign
+		maxWidth float64
+		wantX    []float64
+		wantSize Vec
+	}{
+		{AlignLeft, 100, []float64{0, 0}, Vec{X: 70, Y: 40}},
+		{AlignCenter, 100, []float64{15, 40}, Vec{X: 100, Y: 40}},
+		{AlignRight, 100, []float64{30, 80}, Vec{X: 100, Y: 40}},
+		{AlignJustify, 100, []float64{0, 0}, Vec{X: 100, Y: 40}},
+		{AlignCenter, 0, []float64{0, 25}, Vec{X: 70, Y: 40}},
+		{AlignRight, 0, []float64{0, 50}, Vec{X: 70, Y: 40}},
+	}
+	for _, test := range tests {
+		opts := TextOptions{MaxWidth: test.maxWidth, Align: test.align}
+		l := NewTextLayout(monoFont{}, "a b c d\nab", opts)
+		if _, xs := layoutLines(l); !reflect.DeepEqual(xs, test.wantX) {
+			t.Errorf("align %v in %v: got lines at %v, want %v", test.align, test.maxWidth, xs,
+				test.wantX)
+		}
+		if l.Size() != test.wantSize {
+			t.Errorf("align %v in %v: got size %v, want %v", test.align, test.maxWidth, l.Size(),
+				test.wantSize)
+		}
+	}
+
+	// justified lines spread their spaces out, except for the last line of a paragraph
+	l := NewTextLayout(monoFont{}, "a b c d e", TextOptions{MaxWidth: 60, Align: AlignJustify})
+	var got []float64
+	for _, g := range l.glyphs {
+		if g.r != ' ' {
+			got = append(got, g.pos.X)
+		}
+	}
+	want := []float64{0, 25, 50, 0, 20}
+	if !reflect.DeepEqual(got, want) {
+		t.Errorf("justified: got runes at %v, want %v", got, want)
+	}
+
+	l = NewTextLayout(monoFont{}, "ab", TextOptions{LineSpacing: 1.5})
+	if want := (Vec{X: 20, Y: 30}); l.Size() != want {
+		t.Errorf("line spacing: got size %v, want %v", l.Size(), want)
+	}
+}
+
+func TestTextMarkup(t *testing.T) {
+	red, blue := Colors["red"], Color{R: 0, G: 0, B: 1, A: 1}
+	type style struct {
+		r     rune
+		color Color
+		bold  bool
+	}
+	plain := func(text string) []style {
+		var styles []style
+		for _, r := range text {
+			styles = append(styles, style{r: r})
+		}
+		return styles
+	}
+	tests := []struct {
+		text string
+		want []style
+	}{
+		{"ab", plain("ab")},
+		{"[b]a[/b]b", []style{{'a', Color{}, true}, {'b', Color{}, false}}},
+		{"[color=red]a[color=#0000ff]b[/color]c[/color]d", []style{
+			{'a', red, false}, {'b', blue, false}, {'c', red, false}, {'d', Color{}, false},
+		}},
+		{"[color=red][b]a[/color]b[/b]", []style{{'a', red, true}, {'b', Color{}, true}}},
+		{"[[b]", plain("[b]")},
+		{"[i]", plain("[i]")},
+		{"[/b]", plain("[/b]")},
+		{"[b", plain("[b")},
+		{"[color=nope]", plain("[color=nope]")},
+	}
+	for _, test := range tests {
+		var got []style
+		for _, c := range parseMarkup(test.text) {
+			got = append(got, style{c.r, c.color, c.bold})
+		}
+		if !reflect.DeepEqual(got, test.want) {
+			t.Errorf("%q: got %v, want %v", test.text, got, test.want)
+		}
+	}
+
+	// fake bold runes are one unit wider
+	l := NewTextLayout(monoFont{}, "[b]ab[/b]c", TextOptions{Markup: true})
+	if want := (Vec{X: 32, Y: 20}); l.Size() != want {
+		t.Errorf("fake bold: got size %v, want %v", l.Size(), want)
+	}
+}
+
+func TestParseColor(t *testing.T) {
+	tests := []struct {
+		s    string
+		want Color
+		ok   bool
+	}{
+		{"red", Colors["red"], true},
+		{"#ff8000", Color{R: 1, G: 128.0 / 255, B: 0, A: 1}, true},
+		{"#00000000", Color{}, true},
+		{"#fff", Color{}, false},
+		{"#gggggg", Color{}, false},
+		{"ff8000", Color{}, false},
+		{"nope", Color{}, false},
+	}
+	for _, test := range tests {
+		got, ok := parseColor(test.s)
+		if got != test.want || ok != test.ok {
+			t.Errorf("%q: got %v, %v, want %v, %v", test.s, got, ok, test.want, test.ok)
+		}
+	}
+}
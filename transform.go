@@ -0,0 +1,192 @@
+package gogame
+
+// NewTransform creates a transform drawing to a video output with no transformation.
+func NewTransform(output VideoOutput) *Transform {
+	return &Transform{Matrix: IM, VideoOutput: output}
+}
+
+// Transform draws everything transformed by a matrix using the underlying video output. The
+// matrix can be changed step by step and saved on a stack, which makes it easy to draw
+// hierarchies of parts, such as the limbs of a character:
+//
+//	t.Push()
+//	t.Translate(shoulder)
+//	t.Rotate(armAngle)
+//	drawArm(t) // in the coordinates of the arm
+//	t.Pop()
+//
+// Thickness of lines and strokes isn't transformed. Rectangles, circles and pictures rotated
+// or skewed by the matrix are drawn as polygons and meshes.
+type Transform struct {
+	// Matrix transforms from the coordinates of the drawn things to the coordinates of the
+	// video output.
+	Matrix Matrix
+
+	stack []Matrix
+
+	// VideoOutput is used to actually draw using a transform.
+	VideoOutput
+}
+
+// Push saves the current matrix, so that the matching Pop restores it.
+func (t *Transform) Push() {
+	t.stack = append(t.stack, t.Matrix)
+}
+
+// Pop restores the matrix saved by the matching Push.
+func (t *Transform) Pop() {
+	if len(t.stack) == 0 {
+		panic("nothing to pop, no matrix pushed")
+	}
+	t.Matrix = t.stack[len(t.stack)-1]
+	t.stack = t.stack[:len(t.stack)-1]
+}
+
+// Translate moves the origin of the coordinates by delta (in the current coordinates).
+func (t *Transform) Translate(delta Vec) {
+	t.Matrix = IM.Moved(delta).Chained(t.Matrix)
+}
+
+// Rotate rotates the coordinates around the current origin. Angle is in radians.
+func (t *Transform) Rotate(angle float64) {
+	t.Matrix = IM.Rotated(Vec{}, angle).Chained(t.Matrix)
+}
+
+// Scale scales the coordinates from the current origin, by a different amount on each axis.
+// Negative scale flips the axis.
+func (t *Transform) Scale(scale Vec) {
+	t.Matrix = IM.Scaled(Vec{}, scale).Chained(t.Matrix)
+}
+
+func (t *Transform) projection() projection {
+	return projection{t.Matrix, t.VideoOutput}
+}
+
+// Project transfroms a point to the coordinates of the underlying video output.
+func (t *Transform) Project(x, y float64) (float64, float64) {
+	return t.Matrix.Project(Vec{X: x, Y: y}).XY()
+}
+
+// Unproject transfroms a point from the coordinates of the underlying video output.
+func (t *Transform) Unproject(x, y float64) (float64, float64) {
+	return t.Matrix.Unproject(Vec{X: x, Y: y}).XY()
+}
+
+// ProjectVec transforms a vector to the coordinates of the underlying video output.
+func (t *Transform) ProjectVec(u Vec) Vec {
+	return t.Matrix.Project(u)
+}
+
+// UnprojectVec transforms a vector from the coordinates of the underlying video output.
+func (t *Transform) UnprojectVec(u Vec) Vec {
+	return t.Matrix.Unproject(u)
+}
+
+// OutputRect returns the smallest rectangle containing the unprojected output rectangle of the
+// underlying video output.
+func (t *Transform) OutputRect() Rect {
+	return t.Matrix.unprojectBounds(t.VideoOutput.OutputRect())
+}
+
+// PushClip restricts drawing to the smallest rectangle of the underlying video output
+// containing the projected rectangle.
+func (t *Transform) PushClip(rect Rect) {
+	t.projection().PushClip(rect)
+}
+
+// DrawPoint draws a transformed point using the underlying video output.
+func (t *Transform) DrawPoint(point Vec, color Color) {
+	t.projection().DrawPoint(point, color)
+}
+
+// DrawLine draws a transformed line using the underlying video output.
+func (t *Transform) DrawLine(a, b Vec, thickness float64, color Color) {
+	t.projection().DrawLine(a, b, thickness, color)
+}
+
+// DrawPolygon draws a transformed polygon using the underlying video output.
+func (t *Transform) DrawPolygon(points []Vec, thickness float64, color Color) {
+	t.projection().DrawPolygon(points, thickness, color)
+}
+
+// DrawPolyline draws a transformed polyline using the underlying video output.
+func (t *Transform) DrawPolyline(points []Vec, closed bool, stroke Stroke, color Color) {
+	t.projection().DrawPolyline(points, closed, stroke, color)
+}
+
+// DrawPath draws a transformed path using the underlying video output.
+func (t *Transform) DrawPath(path *Path, color Color) {
+	t.projection().DrawPath(path, color)
+}
+
+// DrawRect draws a transformed rectangle using the underlying video output.
+func (t *Transform) DrawRect(rect Rect, thickness float64, color Color) {
+	t.projection().DrawRect(rect, thickness, color)
+}
+
+// DrawPicture draws a transformed picture using the underlying video output.
+func (t *Transform) DrawPicture(rect Rect, pic *Picture) {
+	t.DrawPictureEx(rect, pic, PictureOptions{Pivot: rect.Size().D(2)})
+}
+
+// DrawPictureEx draws a transformed picture using the underlying video output.
+func (t *Transform) DrawPictureEx(rect Rect, pic *Picture, opts PictureOptions) {
+	t.projection().DrawPictureEx(rect, pic, opts)
+}
+
+// DrawBatch draws a transformed batch using the underlying video output.
+func (t *Transform) DrawBatch(batch *Batch) {
+	t.projection().DrawBatch(batch)
+}
+
+// DrawText draws transformed text using the underlying video output.
+func (t *Transform) DrawText(pos Vec, font Font, text string, color Color) {
+	t.projection().DrawText(pos, font, text, color)
+}
+
+// DrawCircle draws a transformed circle using the underlying video output.
+func (t *Transform) DrawCircle(center Vec, radius, thickness float64, color Color) {
+	t.projection().DrawCircle(center, radius, thickness, color)
+}
+
+// DrawEllipse draws a transformed ellipse using the underlying video output.
+func (t *Transform) DrawEllipse(center, radius Vec, thickness float64, color Color) {
+	t.projection().DrawEllipse(center, radius, thickness, color)
+}
+
+// DrawArc draws a transformed arc using the underlying video output.
+func (t *Transform) DrawArc(center, radius Vec, start, end, thickness float64, color Color) {
+	t.projection().DrawArc(center, radius, start, end, thickness, color)
+}
+
+// DrawPie draws a transformed pie slice using the underlying video output.
+func (t *Transform) DrawPie(center, radius Vec, start, end, thickness float64, color Color) {
+	t.projection().DrawPie(center, radius, start, end, thickness, color)
+}
+
+// DrawRoundedRect draws a transformed rounded rectangle using the underlying video output.
+func (t *Transform) DrawRoundedRect(rect Rect, radius, thickness float64, color Color) {
+	t.projection().DrawRoundedRect(rect, radius, thickness, color)
+}
+
+// DrawBezier draws a transformed Bézier curve using the underlying video output.
+func (t *Transform) DrawBezier(points []Vec, thickness float64, color Color) {
+	t.projection().DrawBezier(points, thickness, color)
+}
+
+// DrawGradientPolygon draws a transformed polygon filled with a gradient using the underlying
+// video output.
+func (t *Transform) DrawGradientPolygon(points []Vec, gradient Gradient) {
+	t.projection().DrawGradientPolygon(points, gradient)
+}
+
+// DrawGradientRect draws a transformed rectangle filled with a gradient using the underlying
+// video output.
+func (t *Transform) DrawGradientRect(rect Rect, gradient Gradient) {
+	t.projection().DrawGradientRect(rect, gradient)
+}
+
+// DrawMesh draws a transformed mesh using the underlying video output.
+func (t *Transform) DrawMesh(mesh *Mesh) {
+	t.projection().DrawMesh(mesh)
+}
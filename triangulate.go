@@ -0,0 +1,117 @@
+package gogame
+
+import "math"
+
+// triangulate splits a simple polygon (one without self-intersections) into triangles. Convex
+// polygons are cut into a fan, others by ear clipping. It returns indices into points, three
+// per triangle.
+func triangulate(points []Vec) []int {
+	if len(points) < 3 {
+		return nil
+	}
+	if convex(points) {
+		triangles := make([]int, 0, 3*(len(points)-2))
+		for i := 2; i < len(points); i++ {
+			triangles = append(triangles, 0, i-1, i)
+		}
+		return triangles
+	}
+
+	// work counter-clockwise (positive area), so that ears are the convex corners
+	remaining := make([]int, len(points))
+	for i := range remaining {
+		remaining[i] = i
+	}
+	if polygonArea(points) < 0 {
+		for i, j := 0, len(remaining)-1; i < j; i, j = i+1, j-1 {
+			remaining[i], remaining[j] = remaining[j], remaining[i]
+		}
+	}
+
+	var triangles []int
+	for len(remaining) > 3 {
+		ear := -1
+		for i := range remaining {
+			if isEar(points, remaining, i) {
+				ear = i
+				break
+			}
+		}
+		if ear < 0 {
+			// not a simple polygon, cut off any corner to make progress
+			ear = 0
+		}
+
+		n := len(remaining)
+		prev, next := remaining[(ear+n-1)%n], remaining[(ear+1)%n]
+		triangles = append(triangles, prev, remaining[ear], next)
+		remaining = append(remaining[:ear], remaining[ear+1:]...)
+	}
+	return append(triangles, remaining...)
+}
+
+// convex checks if a polygon is convex: all of its corners turn the same way and it goes
+// around only once.
+func convex(points []Vec) bool {
+	turns, sign := 0.0, 0.0
+	for i := range points {
+		a, b, c := points[i], points[(i+1)%len(points)], points[(i+2)%len(points)]
+		u, v := b.S(a), c.S(b)
+		turn := cross(u, v)
+		if turn*sign < 0 {
+			return false
+		}
+		if turn != 0 {
+			sign = turn
+		}
+		turns += math.Atan2(turn, dot(u, v))
+	}
+	return math.Abs(math.Abs(turns)-2*math.Pi) < 1e-6
+}
+
+// isEar checks if the corner i of the remaining polygon can be cut off: it's convex and no
+// other corner lies within it.
+func isEar(points []Vec, remaining []int, i int) bool {
+	n := len(remaining)
+	a, b, c := points[remaining[(i+n-1)%n]], points[remaining[i]], points[remaining[(i+1)%n]]
+	if cross(b.S(a), c.S(b)) <= 0 {
+		return false
+	}
+	for j, k := range remaining {
+		if j == i || j == (i+n-1)%n || j == (i+1)%n {
+			continue
+		}
+		if inTriangle(points[k], a, b, c) {
+			return false
+		}
+	}
+	return true
+}
+
+// polygonArea returns the signed area of a polygon. It's positive if the points go
+// counter-clockwise in a coordinate system with the Y axis going up (clockwise on the screen).
+func polygonArea(points []Vec) float64 {
+	if len(points) == 0 {
+		return 0
+	}
+	// relative to the first point, so that far away polygons don't lose precision
+	area := 0.0
+	for i := range points {
+		a, b := points[i].S(points[0]), points[(i+1)%len(points)].S(points[0])
+		area += a.X*b.Y - b.X*a.Y
+	}
+	return area / 2
+}
+
+func cross(u, v Vec) float64 {
+	return u.X*v.Y - u.Y*v.X
+}
+
+func dot(u, v Vec) float64 {
+	return u.X*v.X + u.Y*v.Y
+}
+
+// inTriangle checks if p lies within (or on the edge of) a counter-clockwise triangle.
+func inTriangle(p, a, b, c Vec) bool {
+	return cross(b.S(a), p.S(a)) >= 0 && cross(c.S(b), p.S(b)) >= 0 && cross(a.S(c), p.S(c)) >= 0
+}
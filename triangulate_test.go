@@ -0,0 +1,76 @@
+package gogame
+
+import (
+	"math"
+	"testing"
+)
+
+func TestTriangulate(t *testing.T) {
+	tests := []struct {
+		name   string
+		points []Vec
+		convex bool
+	}{
+		{"triangle", []Vec{{X: 0, Y: 0}, {X: 4, Y: 0}, {X: 0, Y: 3}}, true},
+		{"square", rectPoints(Rect{X: 1, Y: 2, W: 10, H: 5}), true},
+		{"square the other way", reversed(rectPoints(Rect{W: 10, H: 5})), true},
+		{"with a straight corner", []Vec{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 4, Y: 0}, {X: 4, Y: 4}},
+			true},
+		{"ellipse", ellipsePoints(Vec{X: 5, Y: 5}, Vec{X: 20, Y: 8}), true},
+		{"arrow", []Vec{{X: 0, Y: 0}, {X: 10, Y: 5}, {X: 0, Y: 10}, {X: 3, Y: 5}}, false},
+		{"L", []Vec{{X: 0, Y: 0}, {X: 2, Y: 0}, {X: 2, Y: 4}, {X: 6, Y: 4}, {X: 6, Y: 6},
+			{X: 0, Y: 6}}, false},
+		{"comb", []Vec{{X: 0, Y: 0}, {X: 1, Y: 0}, {X: 1, Y: 3}, {X: 2, Y: 3}, {X: 2, Y: 0},
+			{X: 3, Y: 0}, {X: 3, Y: 3}, {X: 4, Y: 3}, {X: 4, Y: 0}, {X: 5, Y: 0}, {X: 5, Y: 4},
+			{X: 0, Y: 4}}, false},
+		{"pie", append([]Vec{{X: 0, Y: 0}}, arcPoints(Vec{}, Vec{X: 5, Y: 5}, 0, 4)...), false},
+	}
+
+	for _, test := range tests {
+		if got := convex(test.points); got != test.convex {
+			t.Errorf("%s: got convex %v, want %v", test.name, got, test.convex)
+		}
+
+		indices := triangulate(test.points)
+		if len(indices) != 3*(len(test.points)-2) {
+			t.Errorf("%s: got %d triangles, want %d", test.name, len(indices)/3,
+				len(test.points)-2)
+			continue
+		}
+
+		// the triangles go the same way as the polygon and cover exactly its area
+		want := polygonArea(test.points)
+		area := 0.0
+		for i := 0; i < len(indices); i += 3 {
+			triangle := []Vec{
+				test.points[indices[i]],
+				test.points[indices[i+1]],
+				test.points[indices[i+2]],
+			}
+			a := polygonArea(triangle)
+			if a*want < 0 {
+				t.Errorf("%s: triangle %v goes the other way", test.name, triangle)
+			}
+			area += a
+		}
+		if math.Abs(area-want) > 1e-9 {
+			t.Errorf("%s: got triangles with area %v, want %v", test.name, area, want)
+		}
+	}
+
+	if got := triangulate([]Vec{{X: 0, Y: 0}, {X: 1, Y: 1}}); got != nil {
+		t.Errorf("two points: got %v, want no triangles", got)
+	}
+}
+
+func TestConvexStar(t *testing.T) {
+	// a pentagram turns the same way at every corner, but goes around twice
+	var star []Vec
+	for i := 0; i < 5; i++ {
+		angle := float64(i) * 4 * math.Pi / 5
+		star = append(star, Vec{X: math.Cos(angle), Y: math.Sin(angle)})
+	}
+	if convex(star) {
+		t.Error("pentagram: got convex")
+	}
+}